@@ -0,0 +1,46 @@
+import * as core from '@actions/core';
+import { AppStoreConnectMonitor } from './monitors/appStoreConnect';
+import { GooglePlayConsoleMonitor } from './monitors/googlePlayConsole';
+import { MonitorConfig } from './types';
+import { HttpRequester } from './utils/http';
+
+/**
+ * Log the name, bundle ID and numeric app ID of every app visible to the App Store Connect
+ * API key, for filling in app-store-app-id
+ */
+export async function runListApps(config: MonitorConfig, http: HttpRequester): Promise<void> {
+  if (!config.appStore) {
+    throw new Error('mode: list-apps needs app-store-issuer-id, app-store-key-id and app-store-private-key');
+  }
+
+  const monitor = new AppStoreConnectMonitor(config.appStore, http);
+  const apps = await monitor.listApps();
+  if (apps.length === 0) {
+    core.warning("No apps are visible to this App Store Connect API key. Check the key's app access");
+    return;
+  }
+
+  core.info(`${apps.length} app(s) visible to App Store Connect key ${monitor.getKeyId()}:`);
+  for (const app of apps) {
+    core.info(`${app.appId}  ${app.bundleId}  ${app.name}`);
+  }
+}
+
+/**
+ * Log every track of the Google Play package with its latest release, for filling in
+ * google-play-tracks
+ */
+export async function runListPlayTracks(config: MonitorConfig, http: HttpRequester): Promise<void> {
+  if (!config.googlePlay) {
+    throw new Error('mode: list-play-tracks needs google-play-package-name and google-play-service-account');
+  }
+
+  const tracks = await new GooglePlayConsoleMonitor(config.googlePlay, http).listTracks();
+  core.info(`${tracks.length} track(s) of ${config.googlePlay.packageName}:`);
+  for (const track of tracks) {
+    const latest = track.latestRelease
+      ? `latest release ${track.latestRelease.name || '(unnamed)'} (${track.latestRelease.status})`
+      : 'no releases';
+    core.info(`${track.track}  ${track.releaseCount} release(s), ${latest}`);
+  }
+}
@@ -0,0 +1,122 @@
+import * as core from '@actions/core';
+import axios from 'axios';
+import { getSkipReason } from './config';
+import { AmazonAppstoreMonitor } from './monitors/amazonAppstore';
+import { AppStoreConnectMonitor } from './monitors/appStoreConnect';
+import { GooglePlayConsoleMonitor } from './monitors/googlePlayConsole';
+import { HuaweiAppGalleryMonitor } from './monitors/huaweiAppGallery';
+import { MultiNotifier } from './notifiers';
+import { MonitorConfig } from './types';
+import { HttpRequester } from './utils/http';
+import { redact } from './utils/redact';
+
+interface DoctorCheck {
+  name: string;
+  // Resolves with optional detail on success
+  run: () => Promise<string | void>;
+}
+
+/**
+ * Check every configured credential and notification channel, logging OK/FAIL for each.
+ * Nothing is notified and the version cache is neither read nor written.
+ * Returns whether every check passed.
+ */
+export async function runDoctor(config: MonitorConfig, http: HttpRequester): Promise<boolean> {
+  const checks: DoctorCheck[] = [];
+
+  if (config.appStore) {
+    const appStore = config.appStore;
+    const monitor = new AppStoreConnectMonitor(appStore, http);
+    for (const appId of appStore.appIds) {
+      checks.push({
+        name: `App Store Connect (app ${appId})`,
+        run: async () => {
+          const appName = await monitor.checkCredentials(appId);
+          return appName ? `app name: ${appName}` : undefined;
+        },
+      });
+    }
+    for (const bundleId of appStore.bundleIds) {
+      checks.push({
+        name: `App Store Connect (bundle ID ${bundleId})`,
+        run: async () => {
+          const appId = await monitor.findAppIdByBundleId(bundleId);
+          const appName = await monitor.checkCredentials(appId);
+          return `app ID: ${appId}${appName ? `, app name: ${appName}` : ''}`;
+        },
+      });
+    }
+  } else {
+    core.info(`SKIP App Store Connect (${getSkipReason(config, 'app-store')})`);
+  }
+
+  if (config.googlePlay) {
+    const googlePlay = config.googlePlay;
+    checks.push({
+      name: `Google Play Console (${googlePlay.packageName})`,
+      run: async () => {
+        const serviceAccount = await new GooglePlayConsoleMonitor(googlePlay, http).checkCredentials();
+        return `service account: ${serviceAccount}`;
+      },
+    });
+  } else {
+    core.info(`SKIP Google Play Console (${getSkipReason(config, 'google-play')})`);
+  }
+
+  if (config.amazon) {
+    const amazon = config.amazon;
+    checks.push({
+      name: `Amazon Appstore (${amazon.appId})`,
+      run: async () => {
+        const clientId = await new AmazonAppstoreMonitor(amazon, http).checkCredentials();
+        return `client ID: ${clientId}`;
+      },
+    });
+  } else {
+    core.info(`SKIP Amazon Appstore (${getSkipReason(config, 'amazon')})`);
+  }
+
+  if (config.huawei) {
+    const huawei = config.huawei;
+    checks.push({
+      name: `Huawei AppGallery Connect (${huawei.appId})`,
+      run: async () => {
+        const clientId = await new HuaweiAppGalleryMonitor(huawei, http).checkCredentials();
+        return `client ID: ${clientId}`;
+      },
+    });
+  } else {
+    core.info(`SKIP Huawei AppGallery Connect (${getSkipReason(config, 'huawei')})`);
+  }
+
+  let failures = 0;
+  for (const check of checks) {
+    try {
+      const detail = await check.run();
+      core.info(`OK   ${check.name}${detail ? ` (${detail})` : ''}`);
+    } catch (error) {
+      failures++;
+      core.error(`FAIL ${check.name}: ${describeError(error)}`);
+    }
+  }
+
+  for (const result of await new MultiNotifier(config, http).validateAll()) {
+    if (result.skipped) {
+      core.info(`SKIP ${result.name} (no validation available)`);
+    } else if (result.error) {
+      failures++;
+      core.error(`FAIL ${result.name}: ${result.error}`);
+    } else {
+      core.info(`OK   ${result.name}`);
+    }
+  }
+
+  return failures === 0;
+}
+
+function describeError(error: unknown): string {
+  if (axios.isAxiosError(error) && error.response) {
+    return `HTTP ${error.response.status}: ${JSON.stringify(error.response.data)}`;
+  }
+  return redact(error instanceof Error ? error.message : error);
+}
@@ -1,249 +1,2469 @@
 import * as core from '@actions/core';
+import { getConfig, getSkipReason } from './config';
+import { runListApps, runListPlayTracks } from './discovery';
+import { runDoctor } from './doctor';
+import { AmazonAppstoreMonitor } from './monitors/amazonAppstore';
 import { AppStoreConnectMonitor } from './monitors/appStoreConnect';
 import { GooglePlayConsoleMonitor } from './monitors/googlePlayConsole';
-import { SlackNotifier } from './notifiers/slack';
-import { AppStoreConfig, GooglePlayConfig, NotificationPayload, SlackConfig } from './types';
-import { VersionCacheManager, VersionCache } from './utils/versionCache';
+import { HuaweiAppGalleryMonitor } from './monitors/huaweiAppGallery';
+import { MultiNotifier, NotificationError } from './notifiers';
+import { OpsGenieClient } from './notifiers/opsgenie';
+import { PagerDutyClient } from './notifiers/pagerduty';
+import { AmazonReviewStatus, AppStoreReviewInfo, AppStoreReviewStatus, BuildProcessingInfo, BuildProcessingState, GooglePlayReviewInfo, InAppPurchaseInfo, Incident, GooglePlayReviewStatus, HuaweiReviewStatus, MonitorConfig, NotificationConfig, NotificationPayload, Notifier, ReviewSubmissionInfo, ReviewSubmissionState, RunSummary, StatusCounts, TestFlightReviewInfo, WatchConfig } from './types';
+import { getApiErrorKind } from './utils/apiError';
+import { createLimiter, Prefetched, prefetch } from './utils/concurrency';
+import { HttpClient } from './utils/http';
+import { pushMetrics } from './utils/metrics';
+import { formatStatus, getStatusCategory, getStatusColor, isActionRequiredStatus, setDisplayTimeZone, setStatusEmojiMap } from './utils/status';
+import { logEvent, setCorrelationId, setLogFormat } from './utils/logger';
+import { isInQuietHours } from './utils/quietHours';
+import { redact } from './utils/redact';
+import { getVersionLabel, VERSION } from './version';
+import {
+  AmazonCacheEntry,
+  AppStoreCacheEntry,
+  BuildProcessingCacheEntry,
+  CACHE_SCHEMA_VERSION,
+  GooglePlayCacheEntry,
+  HuaweiCacheEntry,
+  InAppPurchaseCacheEntry,
+  NotifiableCacheEntry,
+  ReviewSubmissionCacheEntry,
+  SlackThreadEntry,
+  TestFlightCacheEntry,
+  VersionCacheManager,
+  VersionCache,
+} from './utils/versionCache';
+
+// Clients created once per run and shared by every check in watch mode
+interface RunServices {
+  config: MonitorConfig;
+  httpClient: HttpClient;
+  notifier: MultiNotifier;
+  cacheManager: VersionCacheManager;
+  pagerDuty?: PagerDutyClient;
+  opsGenie?: OpsGenieClient;
+}
+
+// Requests started ahead for one App Store app and platform, set for the enabled checks
+interface AppStoreFetches {
+  review: Promise<Prefetched<AppStoreReviewInfo | null>>;
+  testFlight?: Promise<Prefetched<TestFlightReviewInfo | null>>;
+  buildProcessing?: Promise<Prefetched<BuildProcessingInfo | null>>;
+  reviewSubmission?: Promise<Prefetched<ReviewSubmissionInfo | null>>;
+}
+
+// State shared by every app/track checked during a run
+interface RunContext {
+  config: MonitorConfig;
+  notifier: Notifier;
+  pagerDuty?: PagerDutyClient;
+  opsGenie?: OpsGenieClient;
+  cacheManager: VersionCacheManager;
+  previousCache: VersionCache | null;
+  currentCache: VersionCache;
+  summary: RunSummary;
+  // Age of the previous cache when it exceeds cache-max-age-hours
+  monitoringLapsedHours?: number;
+  // First run with notify-on-first-run disabled: record statuses without notifying
+  baselineOnly: boolean;
+  // Hold non-critical notifications in the cache until quiet hours end
+  inQuietHours: boolean;
+}
 
 async function run(): Promise<void> {
   try {
+    core.info(getVersionLabel());
+    core.setOutput('version', VERSION);
+
+    // Get inputs
+    const config = getConfig();
+    setLogFormat(config.logFormat);
+    setCorrelationId(config.http.correlationId);
+    core.info(`Correlation ID: ${config.http.correlationId}`);
+    core.setOutput('correlation-id', config.http.correlationId);
+    setStatusEmojiMap(config.notifications.statusEmojiMap);
+    setDisplayTimeZone(config.notifications.displayTimeZone);
+
+    // Single HTTP client shared by all API integrations, injected as an HttpRequester
+    const httpClient = new HttpClient(config.http);
+
+    if (config.mode === 'doctor') {
+      core.info('Running credential checks (doctor mode)...');
+      if (await runDoctor(config, httpClient)) {
+        core.info('All credential checks passed');
+      } else {
+        core.setFailed('One or more credential checks failed');
+      }
+      return;
+    }
+
+    // Onboarding helpers that print the IDs to configure, without monitoring
+    if (config.mode === 'list-apps') {
+      await runListApps(config, httpClient);
+      return;
+    }
+    if (config.mode === 'list-play-tracks') {
+      await runListPlayTracks(config, httpClient);
+      return;
+    }
+
     // Initialize version cache manager
-    const cacheManager = new VersionCacheManager();
+    const cacheManager = new VersionCacheManager(config.cache, httpClient);
     const previousCache = await cacheManager.loadPreviousVersions();
 
-    const currentCache: VersionCache = {
-      lastChecked: new Date().toISOString(),
+    const notifier = new MultiNotifier(config, httpClient);
+
+    // Fail fast on broken Slack credentials before doing any monitoring work
+    if (config.slack?.validateOnStart) {
+      core.info('Validating notification channels...');
+      await notifier.validate();
+    }
+
+    const services: RunServices = {
+      config,
+      httpClient,
+      notifier,
+      cacheManager,
+      pagerDuty: config.pagerDuty ? new PagerDutyClient(config.pagerDuty, httpClient) : undefined,
+      opsGenie: config.opsGenie ? new OpsGenieClient(config.opsGenie, httpClient) : undefined,
     };
 
-    // Get inputs
-    const appStoreIssuerId = core.getInput('app-store-issuer-id');
-    const appStoreKeyId = core.getInput('app-store-key-id');
-    const appStorePrivateKey = core.getInput('app-store-private-key');
-    const appStoreAppId = core.getInput('app-store-app-id');
+    let apiErrorCount: number;
+    if (config.watch) {
+      apiErrorCount = await watch(services, config.watch, previousCache);
+    } else {
+      const result = await checkStores(services, previousCache);
+      apiErrorCount = result.apiErrorCount;
 
-    const googlePlayPackageName = core.getInput('google-play-package-name');
-    const googlePlayServiceAccount = core.getInput('google-play-service-account');
+      // Save current cache for next run
+      await cacheManager.saveCurrentVersions(result.cache);
+      await pushRunMetrics(services, result.cache);
+    }
 
-    const slackWebhookUrl = core.getInput('slack-webhook-url');
-    const slackBotToken = core.getInput('slack-bot-token');
-    const slackChannel = core.getInput('slack-channel');
-    const slackLanguage = core.getInput('slack-language') as 'en' | 'ja' || 'en';
-    const slackMentionsInput = core.getInput('slack-mentions');
+    // Failures are only warnings unless the workflow opted into failing on them
+    const failures: string[] = [];
+    const failedSendCount = notifier.getFailedSendCount();
+    if (config.failOnNotificationError && failedSendCount > 0) {
+      failures.push(`${failedSendCount} notification send(s) failed (fail-on-notification-error)`);
+    }
+    if (config.failOnApiError && apiErrorCount > 0) {
+      failures.push(`${apiErrorCount} store API check(s) failed (fail-on-api-error)`);
+    }
+    if (failures.length > 0) {
+      core.setFailed(failures.join('; '));
+      return;
+    }
 
-    if (!slackWebhookUrl && !slackBotToken) {
-      throw new Error('Either slack-webhook-url or slack-bot-token is required');
+    core.info('Store review monitoring completed successfully');
+  } catch (error) {
+    if (error instanceof Error) {
+      core.setFailed(redact(error.message));
+    } else {
+      core.setFailed('An unknown error occurred');
     }
+  }
+}
+
+/**
+ * Check every configured app and track once, notifying changes against previousCache.
+ * Returns the new cache for the caller to save, and the number of failed store API checks.
+ */
+async function checkStores(
+  services: RunServices,
+  previousCache: VersionCache | null
+): Promise<{ cache: VersionCache; apiErrorCount: number }> {
+  const { config, httpClient, notifier, cacheManager, pagerDuty, opsGenie } = services;
+
+  const currentCache: VersionCache = {
+    schemaVersion: CACHE_SCHEMA_VERSION,
+    lastChecked: new Date().toISOString(),
+  };
 
-    if (slackBotToken && !slackChannel) {
-      throw new Error('slack-channel is required when using slack-bot-token');
+  // Expose the cache age so a later step can alert on stale monitoring
+  let monitoringLapsedHours: number | undefined;
+  if (previousCache) {
+    const now = new Date(currentCache.lastChecked);
+    const cacheAgeHours = cacheManager.getCacheAgeHours(previousCache, now);
+    if (cacheAgeHours !== undefined) {
+      core.setOutput('cache-age-hours', cacheAgeHours);
     }
+    if (cacheManager.isCacheStale(previousCache, now)) {
+      monitoringLapsedHours = cacheAgeHours;
+    }
+  }
 
-    const slackMentions = slackMentionsInput
-      ? slackMentionsInput.split(',').map(m => m.trim()).filter(m => m.length > 0)
-      : [];
+  const summary: RunSummary = {
+    checkedAt: currentCache.lastChecked,
+    notificationSent: false,
+    appStore: { skipped: !config.appStore, apps: [] },
+    testFlight: { skipped: !config.appStore?.monitorTestFlight, apps: [] },
+    buildProcessing: { skipped: !config.appStore?.monitorBuildProcessing, apps: [] },
+    inAppPurchases: { skipped: !config.appStore?.monitorInAppPurchases, products: [] },
+    reviewSubmissions: { skipped: !config.appStore?.monitorReviewSubmissions, apps: [] },
+    googlePlay: { skipped: !config.googlePlay, tracks: [] },
+    amazon: { skipped: !config.amazon, apps: [] },
+    huawei: { skipped: !config.huawei, apps: [] },
+  };
 
-    const slackConfig: SlackConfig = {
-      webhookUrl: slackWebhookUrl || undefined,
-      botToken: slackBotToken || undefined,
-      channel: slackChannel || undefined,
-      language: slackLanguage,
-      mentions: slackMentions.length > 0 ? slackMentions : undefined,
-    };
+  // Without a previous cache everything looks changed, so the first run can be a silent baseline
+  const baselineOnly = !previousCache && !config.notifications.notifyOnFirstRun;
+  if (baselineOnly) {
+    core.info('No previous cache found, recording current statuses without notifying (notify-on-first-run: false)');
+  }
 
-    const notifier = new SlackNotifier(slackConfig);
+  const inQuietHours =
+    !!config.notifications.quietHours &&
+    isInQuietHours(new Date(currentCache.lastChecked), config.notifications.quietHours);
+  const heldNotifications = previousCache?.heldNotifications || [];
+  if (inQuietHours) {
+    core.info('Within quiet hours, holding non-critical notifications until they end');
+    currentCache.heldNotifications = [...heldNotifications];
+  }
 
-    let appStoreStatusSent = false;
-    let googlePlayStatusSent = false;
+  const context: RunContext = {
+    config,
+    notifier,
+    pagerDuty,
+    opsGenie,
+    cacheManager,
+    previousCache,
+    currentCache,
+    summary,
+    monitoringLapsedHours,
+    baselineOnly,
+    inQuietHours,
+  };
 
-    // Monitor App Store Connect
-    if (appStoreIssuerId && appStoreKeyId && appStorePrivateKey && appStoreAppId) {
-      core.info('Monitoring App Store Connect...');
+  // Failed store API calls (after HTTP retries), for fail-on-api-error
+  let apiErrorCount = 0;
+  // Platforms whose credentials were rejected, with the error, for alert-on-auth-failure
+  const authFailures = new Map<NotificationPayload['platform'], string>();
+  const countApiError = (error: unknown, platform: NotificationPayload['platform']) => {
+    if (!(error instanceof NotificationError)) {
+      apiErrorCount++;
+    }
+    // Expired or revoked credentials, as opposed to an outage or a missing app
+    if (getApiErrorKind(error) === 'auth' && !authFailures.has(platform)) {
+      authFailures.set(platform, `${redact(error)}`);
+    }
+  };
 
-      const appStoreConfig: AppStoreConfig = {
-        issuerId: appStoreIssuerId,
-        keyId: appStoreKeyId,
-        privateKey: appStorePrivateKey,
-        appId: appStoreAppId,
-      };
+  let appStoreStatusSent = false;
+  let testFlightStatusSent = false;
+  let buildProcessingStatusSent = false;
+  let reviewSubmissionStatusSent = false;
+  let inAppPurchaseStatusSent = false;
+  let googlePlayStatusSent = false;
+  let amazonStatusSent = false;
+  let huaweiStatusSent = false;
 
-      const appStoreMonitor = new AppStoreConnectMonitor(appStoreConfig);
+  // Monitor App Store Connect
+  if (config.appStore) {
+    core.info('Monitoring App Store Connect...');
 
-      try {
-        const reviewInfo = await appStoreMonitor.getReviewStatus();
-
-        if (reviewInfo) {
-          core.info(`App Store status: ${reviewInfo.status}`);
-          core.setOutput('app-store-status', reviewInfo.status);
-
-          // Update current cache
-          currentCache.appStore = {
-            appId: reviewInfo.appId,
-            version: reviewInfo.version,
-            buildNumber: reviewInfo.buildNumber,
-            status: reviewInfo.status,
-          };
-
-          // Check if version or build has changed
-          const versionOrBuildChanged = cacheManager.hasVersionOrBuildChanged(
-            'appStore',
-            reviewInfo.version,
-            reviewInfo.buildNumber,
-            previousCache
-          );
-
-          // Check if recovered from rejection (same version/build but status changed from REJECTED to approved)
-          const recoveredFromRejection = cacheManager.hasRecoveredFromRejection(
-            'appStore',
-            reviewInfo.status,
-            previousCache
-          );
-
-          // Check if we should notify (status-based check)
-          const shouldNotify = shouldSendNotification(reviewInfo.status);
-
-          // Notify if: (version/build changed OR recovered from rejection) AND should notify
-          if ((versionOrBuildChanged || recoveredFromRejection) && shouldNotify) {
-            const previousVersion = previousCache?.appStore?.version;
-            const previousBuild = previousCache?.appStore?.buildNumber;
-            const previousStatus = previousCache?.appStore?.status;
-
-            const payload: NotificationPayload = {
-              platform: 'App Store',
-              version: `${reviewInfo.version}${reviewInfo.buildNumber ? ` (${reviewInfo.buildNumber})` : ''}`,
-              currentStatus: reviewInfo.status,
-              previousStatus: previousStatus || undefined,
-            };
-
-            await notifier.sendNotification(payload);
-            appStoreStatusSent = true;
-
-            if (recoveredFromRejection) {
-              core.info(`Sent App Store notification to Slack (recovered from rejection: ${previousStatus} -> ${reviewInfo.status})`);
-            } else {
-              core.info(`Sent App Store notification to Slack (version/build changed: v${previousVersion}(${previousBuild}) -> v${reviewInfo.version}(${reviewInfo.buildNumber}))`);
-            }
-          } else if (!versionOrBuildChanged && !recoveredFromRejection) {
-            core.info('App Store version/build has not changed and not recovered from rejection, skipping notification');
-          } else {
-            core.info('App Store status does not require notification');
-          }
-        } else {
-          core.info('No App Store review information available');
+    const appStoreMonitor = new AppStoreConnectMonitor(config.appStore, httpClient);
+    currentCache.appStore = {};
+    if (config.appStore.monitorTestFlight) {
+      currentCache.testFlight = {};
+    }
+    if (config.appStore.monitorBuildProcessing) {
+      currentCache.buildProcessing = {};
+    }
+    if (config.appStore.monitorInAppPurchases) {
+      currentCache.inAppPurchases = {};
+    }
+    if (config.appStore.monitorReviewSubmissions) {
+      currentCache.reviewSubmissions = {};
+    }
+
+    const appIds = await appStoreMonitor.resolveAppIds((bundleId, error) => {
+      core.warning(`Failed to resolve App Store bundle ID ${bundleId}: ${redact(error)}`);
+      countApiError(error, 'App Store');
+    });
+
+    // Start every app's requests up front, max-concurrency at a time. The results are still
+    // compared, cached and notified one app after another, in the configured order.
+    const limiter = createLimiter(config.maxConcurrency);
+    const fetches = new Map<string, AppStoreFetches>();
+    const inAppPurchaseFetches = new Map<string, Promise<Prefetched<InAppPurchaseInfo[]>>>();
+    for (const appId of appIds) {
+      for (const platform of config.appStore.platforms) {
+        const key = getAppStoreKey(appId, platform);
+        // App names rarely change, so the cached one saves a request per run
+        const review = prefetch(limiter, () =>
+          appStoreMonitor.getReviewStatus(appId, platform, previousCache?.appStore?.[key]?.appName)
+        );
+
+        // With several keys, the first request finds the one that works before any other is
+        // scheduled, so concurrent 401s don't skip keys
+        if (fetches.size === 0 && config.appStore.keys.length > 1) {
+          await review.catch(() => undefined);
         }
-      } catch (error) {
-        core.warning(`Failed to monitor App Store Connect: ${error}`);
+
+        fetches.set(key, {
+          review,
+          testFlight: config.appStore.monitorTestFlight
+            ? prefetch(limiter, () => appStoreMonitor.getTestFlightStatus(appId, platform))
+            : undefined,
+          buildProcessing: config.appStore.monitorBuildProcessing
+            ? prefetch(limiter, () => appStoreMonitor.getBuildProcessingState(appId, platform))
+            : undefined,
+          reviewSubmission: config.appStore.monitorReviewSubmissions
+            ? prefetch(limiter, () => appStoreMonitor.getReviewSubmissionState(appId, platform))
+            : undefined,
+        });
+      }
+      if (config.appStore.monitorInAppPurchases) {
+        inAppPurchaseFetches.set(appId, prefetch(limiter, () => appStoreMonitor.getInAppPurchaseStates(appId)));
       }
-    } else {
-      core.info('Skipping App Store Connect monitoring (missing configuration)');
     }
 
-    // Monitor Google Play Console
-    if (googlePlayPackageName && googlePlayServiceAccount) {
-      core.info('Monitoring Google Play Console...');
+    // Each app and platform is checked and notified independently so one failure doesn't affect the others
+    for (const appId of appIds) {
+      for (const platform of config.appStore.platforms) {
+        const key = getAppStoreKey(appId, platform);
+        const isPrimary = appId === appIds[0] && platform === config.appStore.platforms[0];
+        const appFetches = fetches.get(key)!;
 
-      const googlePlayConfig: GooglePlayConfig = {
-        packageName: googlePlayPackageName,
-        serviceAccount: googlePlayServiceAccount,
-      };
+        try {
+          const sent = await monitorAppStoreApp(context, await appFetches.review, appId, platform, isPrimary);
+          appStoreStatusSent = appStoreStatusSent || sent;
+        } catch (error) {
+          core.warning(`Failed to monitor App Store Connect app ${key}: ${redact(error)}`);
+          countApiError(error, 'App Store');
+          summary.appStore.apps.push({ appId, platform, changed: false, notified: false, error: `${redact(error)}`, errorKind: getApiErrorKind(error) });
 
-      const googlePlayMonitor = new GooglePlayConsoleMonitor(googlePlayConfig);
+          // Keep the previous entry so the next run doesn't treat this app as changed
+          const previousEntry = previousCache?.appStore?.[key];
+          if (previousEntry) {
+            currentCache.appStore[key] = previousEntry;
+          }
+        }
 
-      try {
-        const reviewInfo = await googlePlayMonitor.getReviewStatus();
-
-        if (reviewInfo) {
-          core.info(`Google Play status: ${reviewInfo.status}`);
-          core.setOutput('google-play-status', reviewInfo.status);
-
-          // Update current cache
-          currentCache.googlePlay = {
-            packageName: reviewInfo.packageName,
-            versionCode: reviewInfo.versionCode,
-            versionName: reviewInfo.versionName,
-            status: reviewInfo.status,
-          };
-
-          // Check if version has changed
-          const versionChanged = cacheManager.hasVersionOrBuildChanged(
-            'googlePlay',
-            reviewInfo.versionCode,
-            undefined,
-            previousCache
-          );
-
-          // Check if recovered from rejection
-          const recoveredFromRejection = cacheManager.hasRecoveredFromRejection(
-            'googlePlay',
-            reviewInfo.status,
-            previousCache
-          );
-
-          // Check if we should notify (status-based check)
-          const shouldNotify = shouldSendNotification(reviewInfo.status);
-
-          // Notify if: (version changed OR recovered from rejection) AND should notify
-          if ((versionChanged || recoveredFromRejection) && shouldNotify) {
-            const previousVersionCode = previousCache?.googlePlay?.versionCode;
-            const previousStatus = previousCache?.googlePlay?.status;
-
-            const payload: NotificationPayload = {
-              platform: 'Google Play',
-              version: reviewInfo.versionCode.toString(),
-              currentStatus: reviewInfo.status,
-              previousStatus: previousStatus || undefined,
-            };
-
-            await notifier.sendNotification(payload);
-            googlePlayStatusSent = true;
-
-            if (recoveredFromRejection) {
-              core.info(`Sent Google Play notification to Slack (recovered from rejection: ${previousStatus} -> ${reviewInfo.status})`);
-            } else {
-              core.info(`Sent Google Play notification to Slack (version changed: ${previousVersionCode} -> ${reviewInfo.versionCode})`);
+        if (config.appStore.monitorTestFlight) {
+          try {
+            const sent = await monitorTestFlightApp(context, await appFetches.testFlight!, appId, platform, isPrimary);
+            testFlightStatusSent = testFlightStatusSent || sent;
+          } catch (error) {
+            core.warning(`Failed to monitor TestFlight for app ${key}: ${redact(error)}`);
+            countApiError(error, 'App Store');
+            summary.testFlight.apps.push({ appId, platform, changed: false, notified: false, error: `${redact(error)}`, errorKind: getApiErrorKind(error) });
+
+            const previousEntry = previousCache?.testFlight?.[key];
+            if (previousEntry) {
+              currentCache.testFlight = { ...currentCache.testFlight, [key]: previousEntry };
+            }
+          }
+        }
+
+        if (config.appStore.monitorBuildProcessing) {
+          try {
+            const sent = await monitorBuildProcessing(context, await appFetches.buildProcessing!, appId, platform, isPrimary);
+            buildProcessingStatusSent = buildProcessingStatusSent || sent;
+          } catch (error) {
+            core.warning(`Failed to monitor build processing for app ${key}: ${redact(error)}`);
+            countApiError(error, 'App Store');
+            summary.buildProcessing.apps.push({ appId, platform, changed: false, notified: false, error: `${redact(error)}`, errorKind: getApiErrorKind(error) });
+
+            const previousEntry = previousCache?.buildProcessing?.[key];
+            if (previousEntry) {
+              currentCache.buildProcessing = { ...currentCache.buildProcessing, [key]: previousEntry };
             }
-          } else if (!versionChanged && !recoveredFromRejection) {
-            core.info('Google Play version has not changed and not recovered from rejection, skipping notification');
-          } else {
-            core.info('Google Play status does not require notification');
           }
-        } else {
-          core.info('No Google Play review information available');
         }
+
+        if (config.appStore.monitorReviewSubmissions) {
+          try {
+            const sent = await monitorReviewSubmission(context, await appFetches.reviewSubmission!, appId, platform, isPrimary);
+            reviewSubmissionStatusSent = reviewSubmissionStatusSent || sent;
+          } catch (error) {
+            core.warning(`Failed to monitor review submissions for app ${key}: ${redact(error)}`);
+            countApiError(error, 'App Store');
+            summary.reviewSubmissions.apps.push({ appId, platform, changed: false, notified: false, error: `${redact(error)}`, errorKind: getApiErrorKind(error) });
+
+            const previousEntry = previousCache?.reviewSubmissions?.[key];
+            if (previousEntry) {
+              currentCache.reviewSubmissions = { ...currentCache.reviewSubmissions, [key]: previousEntry };
+            }
+          }
+        }
+      }
+
+      // Products belong to the app rather than to one of its platforms
+      if (config.appStore.monitorInAppPurchases) {
+        try {
+          const sent = await monitorInAppPurchases(context, await inAppPurchaseFetches.get(appId)!, appId, appId === appIds[0]);
+          inAppPurchaseStatusSent = inAppPurchaseStatusSent || sent;
+        } catch (error) {
+          core.warning(`Failed to monitor in-app purchases for app ${appId}: ${redact(error)}`);
+          countApiError(error, 'App Store');
+          summary.inAppPurchases.products.push({ appId, changed: false, notified: false, error: `${redact(error)}`, errorKind: getApiErrorKind(error) });
+
+          for (const [productKey, previousEntry] of Object.entries(previousCache?.inAppPurchases || {})) {
+            if (previousEntry.appId === appId) {
+              currentCache.inAppPurchases = { ...currentCache.inAppPurchases, [productKey]: previousEntry };
+            }
+          }
+        }
+      }
+    }
+  } else {
+    core.info(`Skipping App Store Connect monitoring (${getSkipReason(config, 'app-store')})`);
+  }
+
+  // Monitor Google Play Console
+  if (config.googlePlay) {
+    core.info('Monitoring Google Play Console...');
+
+    const googlePlayMonitor = new GooglePlayConsoleMonitor(config.googlePlay, httpClient);
+    currentCache.googlePlay = {};
+
+    let trackInfos: GooglePlayReviewInfo[] = [];
+    const fetchStartedAt = Date.now();
+    let fetchDurationMs = 0;
+    try {
+      const packageName = config.googlePlay.packageName;
+      const cachedAppName = Object.values(previousCache?.googlePlay || {}).find(
+        (entry) => entry.packageName === packageName && entry.appName
+      )?.appName;
+      trackInfos = await googlePlayMonitor.getReviewStatus(cachedAppName);
+      fetchDurationMs = Date.now() - fetchStartedAt;
+
+      if (trackInfos.length === 0) {
+        core.info('No Google Play review information available');
+      }
+    } catch (error) {
+      core.warning(`Failed to monitor Google Play Console: ${redact(error)}`);
+      countApiError(error, 'Google Play');
+      for (const track of config.googlePlay.tracks) {
+        summary.googlePlay.tracks.push({
+          track,
+          packageName: config.googlePlay.packageName,
+          changed: false,
+          notified: false,
+          error: `${redact(error)}`,
+          errorKind: getApiErrorKind(error),
+        });
+      }
+
+      // Keep the previous entries so the next run doesn't treat every track as changed
+      if (previousCache?.googlePlay) {
+        currentCache.googlePlay = { ...previousCache.googlePlay };
+      }
+    }
+
+    for (const reviewInfo of trackInfos) {
+      try {
+        const sent = await monitorGooglePlayTrack(
+          context,
+          reviewInfo,
+          reviewInfo.track === config.googlePlay.tracks[0],
+          fetchDurationMs
+        );
+        googlePlayStatusSent = googlePlayStatusSent || sent;
       } catch (error) {
-        core.warning(`Failed to monitor Google Play Console: ${error}`);
+        core.warning(`Failed to monitor Google Play ${reviewInfo.track} track: ${redact(error)}`);
+        countApiError(error, 'Google Play');
+        summary.googlePlay.tracks.push({
+          track: reviewInfo.track,
+          packageName: reviewInfo.packageName,
+          versionCode: reviewInfo.versionCode,
+          status: reviewInfo.status,
+          changed: false,
+          notified: false,
+          error: `${redact(error)}`,
+          errorKind: getApiErrorKind(error),
+        });
+
+        const previousEntry = previousCache?.googlePlay?.[reviewInfo.track];
+        if (previousEntry) {
+          currentCache.googlePlay[reviewInfo.track] = previousEntry;
+        }
       }
+    }
+  } else {
+    core.info(`Skipping Google Play Console monitoring (${getSkipReason(config, 'google-play')})`);
+  }
+
+  // Monitor Amazon Appstore
+  if (config.amazon) {
+    core.info('Monitoring Amazon Appstore...');
+
+    const amazonMonitor = new AmazonAppstoreMonitor(config.amazon, httpClient);
+    const appId = config.amazon.appId;
+    currentCache.amazon = {};
+
+    try {
+      amazonStatusSent = await monitorAmazonApp(context, amazonMonitor, appId);
+    } catch (error) {
+      core.warning(`Failed to monitor Amazon Appstore app ${appId}: ${redact(error)}`);
+      countApiError(error, 'Amazon Appstore');
+      summary.amazon.apps.push({ appId, changed: false, notified: false, error: `${redact(error)}`, errorKind: getApiErrorKind(error) });
+
+      const previousEntry = previousCache?.amazon?.[appId];
+      if (previousEntry) {
+        currentCache.amazon[appId] = previousEntry;
+      }
+    }
+  } else {
+    core.info(`Skipping Amazon Appstore monitoring (${getSkipReason(config, 'amazon')})`);
+  }
+
+  // Monitor Huawei AppGallery Connect
+  if (config.huawei) {
+    core.info('Monitoring Huawei AppGallery Connect...');
+
+    const huaweiMonitor = new HuaweiAppGalleryMonitor(config.huawei, httpClient);
+    const appId = config.huawei.appId;
+    currentCache.huawei = {};
+
+    try {
+      huaweiStatusSent = await monitorHuaweiApp(context, huaweiMonitor, appId);
+    } catch (error) {
+      core.warning(`Failed to monitor Huawei AppGallery app ${appId}: ${redact(error)}`);
+      countApiError(error, 'Huawei AppGallery');
+      summary.huawei.apps.push({ appId, changed: false, notified: false, error: `${redact(error)}`, errorKind: getApiErrorKind(error) });
+
+      const previousEntry = previousCache?.huawei?.[appId];
+      if (previousEntry) {
+        currentCache.huawei[appId] = previousEntry;
+      }
+    }
+  } else {
+    core.info(`Skipping Huawei AppGallery Connect monitoring (${getSkipReason(config, 'huawei')})`);
+  }
+
+  const authAlertSent = config.notifications.alertOnAuthFailure
+    ? await sendAuthFailureAlerts(context, authFailures)
+    : false;
+
+  // Send what was held once quiet hours are over, keeping it for the next run if that fails
+  let heldNotificationsSent = false;
+  if (!inQuietHours && heldNotifications.length > 0) {
+    try {
+      await notifier.sendHeldNotifications(heldNotifications);
+      heldNotificationsSent = true;
+    } catch (error) {
+      core.warning(`${redact(error)}`);
+      currentCache.heldNotifications = heldNotifications;
+    }
+  }
+
+  // Sent before saving the cache, so a failed digest is notified again on the next run
+  const statusCounts = countStatuses(summary);
+  await notifier.flushDigest(statusCounts);
+
+  // Set output
+  summary.notificationSent =
+    appStoreStatusSent ||
+    testFlightStatusSent ||
+    buildProcessingStatusSent ||
+    reviewSubmissionStatusSent ||
+    inAppPurchaseStatusSent ||
+    googlePlayStatusSent ||
+    amazonStatusSent ||
+    huaweiStatusSent ||
+    authAlertSent ||
+    heldNotificationsSent;
+  core.setOutput('notification-sent', summary.notificationSent);
+
+  // Carried over so the interval counts from the last heartbeat rather than the last run
+  currentCache.lastHeartbeat = previousCache?.lastHeartbeat;
+  if (!summary.notificationSent && apiErrorCount === 0 && isHeartbeatDue(context)) {
+    const checkedCount =
+      statusCounts.unchanged + Object.values(statusCounts.changed).reduce((sum, count) => sum + count, 0);
+    try {
+      await notifier.sendHeartbeat(checkedCount);
+      currentCache.lastHeartbeat = currentCache.lastChecked;
+    } catch (error) {
+      // Not recorded, so the next run tries again
+      core.warning(`${redact(error)}`);
+    }
+  }
+
+  core.setOutput('skipped-platforms', config.skippedPlatforms.map((skipped) => skipped.platform).join(','));
+  core.setOutput('summary-json', JSON.stringify(summary));
+
+  return { cache: currentCache, apiErrorCount };
+}
+
+/**
+ * Whether heartbeat-interval-hours have passed since the last heartbeat. None is sent on a
+ * baseline run or within quiet hours.
+ */
+function isHeartbeatDue(context: RunContext): boolean {
+  const intervalHours = context.config.notifications.heartbeatIntervalHours;
+  if (intervalHours <= 0 || context.baselineOnly || context.inQuietHours) {
+    return false;
+  }
+
+  const lastHeartbeat = context.previousCache?.lastHeartbeat;
+  if (!lastHeartbeat) {
+    return true;
+  }
+  const elapsedHours =
+    (new Date(context.currentCache.lastChecked).getTime() - new Date(lastHeartbeat).getTime()) / (60 * 60 * 1000);
+  return elapsedHours >= intervalHours;
+}
+
+/**
+ * Count the run's apps, tracks and products by status, once every platform has been checked
+ */
+function countStatuses(summary: RunSummary): StatusCounts {
+  const counts: StatusCounts = { changed: {}, unchanged: 0, failed: 0 };
+  const entries: { status?: string; changed: boolean; error?: string }[] = [
+    ...summary.appStore.apps,
+    ...summary.testFlight.apps,
+    ...summary.buildProcessing.apps,
+    ...summary.inAppPurchases.products,
+    ...summary.reviewSubmissions.apps,
+    ...summary.googlePlay.tracks,
+    ...summary.amazon.apps,
+    ...summary.huawei.apps,
+  ];
+
+  for (const entry of entries) {
+    if (entry.error) {
+      counts.failed++;
+    } else if (!entry.changed || !entry.status) {
+      counts.unchanged++;
     } else {
-      core.info('Skipping Google Play Console monitoring (missing configuration)');
+      counts.changed[entry.status] = (counts.changed[entry.status] || 0) + 1;
     }
+  }
+  return counts;
+}
 
-    // Save current cache for next run
-    await cacheManager.saveCurrentVersions(currentCache);
 
-    // Set output
-    core.setOutput('notification-sent', appStoreStatusSent || googlePlayStatusSent);
+/**
+ * Alert about each platform whose credentials were rejected, once until its checks pass
+ * again. Quiet hours don't apply, since monitoring is down until the credentials are fixed.
+ * Returns whether an alert was sent.
+ */
+async function sendAuthFailureAlerts(
+  context: RunContext,
+  authFailures: Map<NotificationPayload['platform'], string>
+): Promise<boolean> {
+  const alerted: string[] = [];
+  let sent = false;
 
-    core.info('Store review monitoring completed successfully');
+  for (const [platform, detail] of authFailures) {
+    if (context.previousCache?.authFailureAlerts?.includes(platform)) {
+      core.info(`${platform} credentials are still invalid, skipping the alert already sent`);
+      alerted.push(platform);
+      continue;
+    }
+
+    try {
+      await context.notifier.sendNotification({
+        platform: platform,
+        version: '-',
+        currentStatus: 'CREDENTIALS_INVALID',
+        rejectionReason: detail,
+        credentialsInvalid: true,
+      });
+      core.info(`Sent the ${platform} invalid credentials alert`);
+      alerted.push(platform);
+      sent = true;
+    } catch (error) {
+      // Not recorded, so the next run tries again
+      core.warning(`Failed to send the ${platform} invalid credentials alert: ${redact(error)}`);
+    }
+  }
+
+  context.currentCache.authFailureAlerts = alerted.length > 0 ? alerted : undefined;
+  return sent;
+}
+
+/**
+ * Check the stores every watch-interval-seconds until SIGTERM or SIGINT. The cache stays in
+ * memory between checks and is saved every watch-save-interval-seconds and before exiting.
+ * Returns the number of failed store API checks across all iterations.
+ */
+async function watch(services: RunServices, watchConfig: WatchConfig, initialCache: VersionCache | null): Promise<number> {
+  const { cacheManager } = services;
+
+  let stopRequested = false;
+  let wake: (() => void) | undefined;
+  const stop = (signal: string) => {
+    core.info(`Received ${signal}, stopping after saving the version cache`);
+    stopRequested = true;
+    wake?.();
+  };
+  process.once('SIGTERM', () => stop('SIGTERM'));
+  process.once('SIGINT', () => stop('SIGINT'));
+
+  core.info(`Watching for review status changes every ${watchConfig.intervalSeconds} seconds`);
+
+  let cache = initialCache;
+  let unsaved = false;
+  let lastSavedAt = Date.now();
+  let apiErrorCount = 0;
+
+  for (let iteration = 1; !stopRequested; iteration++) {
+    core.info(`Watch iteration ${iteration}...`);
+    try {
+      const result = await checkStores(services, cache);
+      cache = result.cache;
+      unsaved = true;
+      apiErrorCount += result.apiErrorCount;
+      await pushRunMetrics(services, result.cache);
+    } catch (error) {
+      // The previous cache is kept, so whatever failed to notify is notified in the next iteration
+      core.warning(`Watch iteration ${iteration} failed: ${redact(error)}`);
+    }
+
+    if (cache && unsaved && Date.now() - lastSavedAt >= watchConfig.saveIntervalSeconds * 1000) {
+      await cacheManager.saveCurrentVersions(cache);
+      unsaved = false;
+      lastSavedAt = Date.now();
+    }
+
+    if (!stopRequested) {
+      await new Promise<void>((resolve) => {
+        const timer = setTimeout(resolve, watchConfig.intervalSeconds * 1000);
+        wake = () => {
+          clearTimeout(timer);
+          resolve();
+        };
+      });
+      wake = undefined;
+    }
+  }
+
+  if (cache && unsaved) {
+    await cacheManager.saveCurrentVersions(cache);
+  }
+  return apiErrorCount;
+}
+
+// Metrics are best-effort, like the cache upload
+async function pushRunMetrics(services: RunServices, cache: VersionCache): Promise<void> {
+  const { config, cacheManager, httpClient } = services;
+  if (!config.metrics) {
+    return;
+  }
+
+  try {
+    await pushMetrics(config.metrics, cache, cacheManager, httpClient);
   } catch (error) {
-    if (error instanceof Error) {
-      core.setFailed(error.message);
+    core.warning(`Failed to push metrics to ${config.metrics.pushgatewayUrl}: ${redact(error)}`);
+  }
+}
+
+/**
+ * Check a single App Store app, update its cache entry and notify if needed.
+ * Returns whether a notification was sent.
+ */
+async function monitorAppStoreApp(
+  context: RunContext,
+  fetched: Prefetched<AppStoreReviewInfo | null>,
+  appId: string,
+  platform: string,
+  isPrimary: boolean
+): Promise<boolean> {
+  const { config, cacheManager, previousCache, currentCache, summary } = context;
+  const key = getAppStoreKey(appId, platform);
+  const reviewInfo = fetched.value;
+
+  if (!reviewInfo) {
+    core.info(`No App Store review information available for app ${key}`);
+    summary.appStore.apps.push({ appId, platform, changed: false, notified: false });
+    return false;
+  }
+
+  const eventFields = {
+    platform: 'App Store',
+    appId,
+    appPlatform: platform,
+    version: reviewInfo.version,
+    buildNumber: reviewInfo.buildNumber,
+    status: reviewInfo.status,
+  };
+  logEvent('status_fetched', `App Store status for app ${key}: ${reviewInfo.status}`, {
+    ...eventFields,
+    durationMs: fetched.durationMs,
+  });
+  core.setOutput(`app-store-status-${key}`, reviewInfo.status);
+  if (isPrimary) {
+    // Keep the single-app output for backward compatibility
+    core.setOutput('app-store-status', reviewInfo.status);
+  }
+  setDerivedStatusOutputs('app-store', key, reviewInfo.status, isPrimary);
+
+  const previousEntry = previousCache?.appStore?.[key];
+
+  const history = cacheManager.appendHistory(previousEntry?.history, {
+    status: reviewInfo.status,
+    version: reviewInfo.version,
+    build: reviewInfo.buildNumber,
+    timestamp: currentCache.lastChecked,
+  });
+  const flapping =
+    config.cache.flapping && cacheManager.detectFlapping(history, config.cache.flapping, new Date(currentCache.lastChecked))
+      ? config.cache.flapping
+      : undefined;
+
+  const duration = cacheManager.getCurrentStatusDuration(history, new Date(currentCache.lastChecked));
+  if (duration !== undefined) {
+    core.setOutput(`app-store-duration-${key}`, duration);
+    if (isPrimary) {
+      core.setOutput('app-store-duration', duration);
+    }
+  }
+
+  // Update current cache
+  const cacheEntry: AppStoreCacheEntry = {
+    appId: reviewInfo.appId,
+    appName: reviewInfo.appName,
+    platform: platform,
+    version: reviewInfo.version,
+    buildNumber: reviewInfo.buildNumber,
+    status: reviewInfo.status,
+    history: history,
+    liveVersion: reviewInfo.status === AppStoreReviewStatus.READY_FOR_SALE ? reviewInfo.version : previousEntry?.liveVersion,
+    slackThread: getSlackThread(previousEntry, reviewInfo.version),
+    lastNotification: previousEntry?.lastNotification,
+    slackMessageKey: previousEntry?.slackMessageKey,
+  };
+  currentCache.appStore = {
+    ...currentCache.appStore,
+    [key]: cacheEntry,
+  };
+
+  // Check if version or build has changed
+  const versionOrBuildChanged = cacheManager.hasVersionOrBuildChanged(
+    'appStore',
+    reviewInfo.version,
+    reviewInfo.buildNumber,
+    previousEntry
+  );
+  cacheEntry.rejectionCount = cacheManager.countRejections(reviewInfo.status, versionOrBuildChanged, previousEntry);
+  cacheEntry.consecutiveUnchangedRuns = cacheManager.countUnchangedRuns(
+    reviewInfo.status,
+    versionOrBuildChanged,
+    previousEntry
+  );
+  core.setOutput(`app-store-unchanged-runs-${key}`, cacheEntry.consecutiveUnchangedRuns);
+  if (isPrimary) {
+    core.setOutput('app-store-unchanged-runs', cacheEntry.consecutiveUnchangedRuns);
+  }
+
+  // Check if recovered from rejection (same version/build but status changed from REJECTED to approved)
+  const recoveredFromRejection = cacheManager.hasRecoveredFromRejection(
+    'appStore',
+    reviewInfo.status,
+    previousEntry
+  );
+
+  // Check if regressed from an approved status (always notified, regardless of version change)
+  const regressedFromApproval = cacheManager.hasRegressedFromApproval(
+    'appStore',
+    reviewInfo.status,
+    previousEntry
+  );
+
+  const changed = versionOrBuildChanged || recoveredFromRejection;
+  core.setOutput(`app-store-changed-${key}`, changed);
+  core.setOutput(`app-store-previous-status-${key}`, previousEntry?.status || '');
+  if (isPrimary) {
+    core.setOutput('app-store-changed', changed);
+    core.setOutput('app-store-previous-status', previousEntry?.status || '');
+  }
+
+  // Check if the app just entered review (e.g. waiting_for_review -> in_review)
+  const enteredReview =
+    config.notifications.notifyOnInReview &&
+    isInReviewStatus(reviewInfo.status) &&
+    cacheManager.hasStatusChanged(reviewInfo.status, previousEntry);
+
+  // Check if the version just became ready for a manual release, which usually happens
+  // without a version change (in_review -> pending_developer_release)
+  const enteredActionRequired =
+    isActionRequiredStatus(reviewInfo.status) && cacheManager.hasStatusChanged(reviewInfo.status, previousEntry);
+
+  // Check if the version just went live, which a manual release does without a version change
+  // (pending_developer_release -> ready_for_sale). Not on an entry's first check, when the
+  // version may have been live for a long time, and only once per version.
+  const nowLive =
+    !!previousEntry &&
+    reviewInfo.status === AppStoreReviewStatus.READY_FOR_SALE &&
+    previousEntry.status !== AppStoreReviewStatus.READY_FOR_SALE &&
+    previousEntry.liveVersion !== reviewInfo.version;
+
+  // Check if we should notify (status-based check)
+  const shouldNotify = shouldSendNotification(reviewInfo.status, config.notifications);
+  // With notify-on-any-version-change, a new version/build notifies whatever its status
+  // (not on an entry's first check, which has nothing to compare against)
+  const anyVersionChange = config.notifications.notifyOnAnyVersionChange && !!previousEntry && versionOrBuildChanged;
+  // With status-reminder-interval-hours, an unchanged status is notified again once the interval has passed
+  const reminderHours = getReminderHours(context, previousEntry, reviewInfo.version, reviewInfo.status);
+
+  const summaryEntry = {
+    appId,
+    platform,
+    version: reviewInfo.version,
+    buildNumber: reviewInfo.buildNumber,
+    status: reviewInfo.status,
+    changed: versionOrBuildChanged || cacheManager.hasStatusChanged(reviewInfo.status, previousEntry),
+    notified: false,
+  };
+  summary.appStore.apps.push(summaryEntry);
+
+  await updateIncidents(
+    context,
+    {
+      platform: 'App Store',
+      subject: key,
+      version: reviewInfo.version,
+      status: reviewInfo.status,
+      previousStatus: previousEntry?.status,
+    },
+    summaryEntry.changed,
+    recoveredFromRejection
+  );
+
+  // With notify-on-transition-to, only a status change into one of its states notifies
+  const transitionMatch = getTransitionMatch(context, reviewInfo.status, previousEntry);
+
+  // Notify if: regressed OR any version change OR reminder due OR ((version/build changed OR recovered from rejection OR entered review OR awaiting release OR went live) AND should notify)
+  if (
+    transitionMatch ??
+    (regressedFromApproval ||
+      anyVersionChange ||
+      reminderHours !== undefined ||
+      ((versionOrBuildChanged || recoveredFromRejection || enteredReview || enteredActionRequired || nowLive) && shouldNotify))
+  ) {
+    const previousVersion = previousEntry?.version;
+    const previousBuild = previousEntry?.buildNumber;
+    const previousStatus = previousEntry?.status;
+
+    const payload: NotificationPayload = {
+      platform: 'App Store',
+      version: `${reviewInfo.version}${reviewInfo.buildNumber ? ` (${reviewInfo.buildNumber})` : ''}${getPlatformSuffix(config, platform)}`,
+      currentStatus: reviewInfo.status,
+      previousStatus: previousStatus || undefined,
+      rejectionReason: reviewInfo.rejectionReason,
+      regression: regressedFromApproval,
+      flapping: flapping,
+      reminderHours: reminderHours,
+      appName: reviewInfo.appName,
+      releaseType: reviewInfo.releaseType,
+      phasedRelease: reviewInfo.phasedRelease,
+      earliestReleaseDate: reviewInfo.earliestReleaseDate,
+      submittedAt: reviewInfo.submittedDate,
+      daysInReview: getDaysInReview(reviewInfo.submittedDate, reviewInfo.status, previousStatus, currentCache.lastChecked),
+      actionRequired: isActionRequiredStatus(reviewInfo.status),
+      nowLive: nowLive,
+      storeUrl: nowLive ? `${APP_STORE_URL}/app/id${appId}` : undefined,
+      consoleUrl: `${APP_STORE_CONNECT_URL}/apps/${appId}/appstore`,
+      event: {
+        appId,
+        version: reviewInfo.version,
+        buildNumber: reviewInfo.buildNumber,
+        changed: summaryEntry.changed,
+        recovered: recoveredFromRejection,
+      },
+    };
+
+    if (!(await sendNotification(context, payload, cacheEntry, reviewInfo.version))) {
+      return false;
+    }
+    summaryEntry.notified = true;
+
+    let reason: string;
+    if (transitionMatch) {
+      reason = `transition to ${reviewInfo.status}: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (regressedFromApproval) {
+      reason = `regressed from approval: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (recoveredFromRejection) {
+      reason = `recovered from rejection: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (nowLive && !versionOrBuildChanged) {
+      reason = `now live: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if ((enteredReview || enteredActionRequired) && !versionOrBuildChanged) {
+      reason = `review status changed: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (reminderHours !== undefined) {
+      reason = `reminder: ${reviewInfo.status} unchanged for ${reminderHours} hours`;
     } else {
-      core.setFailed('An unknown error occurred');
+      reason = `version/build changed: v${previousVersion}(${previousBuild}) -> v${reviewInfo.version}(${reviewInfo.buildNumber})`;
     }
+    logEvent('notification_sent', `Sent App Store notification for app ${key} (${reason})`, {
+      ...eventFields,
+      previousStatus,
+      changed: summaryEntry.changed,
+    });
+    return true;
   }
+
+  const skippedFields = { ...eventFields, previousStatus: previousEntry?.status, changed: summaryEntry.changed };
+  if (!versionOrBuildChanged && !recoveredFromRejection && !enteredReview && !enteredActionRequired) {
+    logEvent(
+      'notification_skipped',
+      `App Store version/build for app ${key} has not changed and not recovered from rejection, skipping notification`,
+      skippedFields
+    );
+  } else {
+    logEvent('notification_skipped', `App Store status for app ${key} does not require notification`, skippedFields);
+  }
+  return false;
+}
+
+/**
+ * Check the TestFlight beta review of a single app's latest build, tracked separately
+ * from its App Store review so beta approvals aren't mistaken for production ones.
+ * Returns whether a notification was sent.
+ */
+async function monitorTestFlightApp(
+  context: RunContext,
+  fetched: Prefetched<TestFlightReviewInfo | null>,
+  appId: string,
+  platform: string,
+  isPrimary: boolean
+): Promise<boolean> {
+  const { config, cacheManager, previousCache, currentCache, summary } = context;
+  const reviewInfo = fetched.value;
+  const key = getAppStoreKey(appId, platform);
+
+  if (!reviewInfo) {
+    core.info(`No TestFlight beta review information available for app ${key}`);
+    summary.testFlight.apps.push({ appId, platform, changed: false, notified: false });
+    return false;
+  }
+
+  const eventFields = {
+    platform: 'TestFlight',
+    appId,
+    appPlatform: platform,
+    version: reviewInfo.version,
+    buildNumber: reviewInfo.buildNumber,
+    status: reviewInfo.status,
+  };
+  logEvent('status_fetched', `TestFlight status for app ${key}: ${reviewInfo.status}`, {
+    ...eventFields,
+    durationMs: fetched.durationMs,
+  });
+  core.setOutput(`testflight-status-${key}`, reviewInfo.status);
+  if (isPrimary) {
+    core.setOutput('testflight-status', reviewInfo.status);
+  }
+
+  const previousEntry = previousCache?.testFlight?.[key];
+
+  const history = cacheManager.appendHistory(previousEntry?.history, {
+    status: reviewInfo.status,
+    version: reviewInfo.version,
+    build: reviewInfo.buildNumber,
+    timestamp: currentCache.lastChecked,
+  });
+  const flapping =
+    config.cache.flapping && cacheManager.detectFlapping(history, config.cache.flapping, new Date(currentCache.lastChecked))
+      ? config.cache.flapping
+      : undefined;
+
+  // Update current cache
+  const cacheEntry: TestFlightCacheEntry = {
+    appId: reviewInfo.appId,
+    platform: platform,
+    version: reviewInfo.version,
+    buildNumber: reviewInfo.buildNumber,
+    status: reviewInfo.status,
+    history: history,
+    slackThread: getSlackThread(previousEntry, reviewInfo.version),
+    lastNotification: previousEntry?.lastNotification,
+    slackMessageKey: previousEntry?.slackMessageKey,
+  };
+  currentCache.testFlight = {
+    ...currentCache.testFlight,
+    [key]: cacheEntry,
+  };
+
+  const buildChanged = cacheManager.hasVersionOrBuildChanged(
+    'testFlight',
+    reviewInfo.version,
+    reviewInfo.buildNumber,
+    previousEntry
+  );
+  cacheEntry.rejectionCount = cacheManager.countRejections(reviewInfo.status, buildChanged, previousEntry);
+  const recoveredFromRejection = cacheManager.hasRecoveredFromRejection(
+    'testFlight',
+    reviewInfo.status,
+    previousEntry
+  );
+  const regressedFromApproval = cacheManager.hasRegressedFromApproval(
+    'testFlight',
+    reviewInfo.status,
+    previousEntry
+  );
+
+  // Beta review moves through several states per build, so any status change counts
+  const statusChanged = cacheManager.hasStatusChanged(reviewInfo.status, previousEntry);
+  const shouldNotify = shouldSendNotification(reviewInfo.status, config.notifications);
+  // With notify-on-any-version-change, a new version/build notifies whatever its status
+  // (not on an entry's first check, which has nothing to compare against)
+  const anyVersionChange = config.notifications.notifyOnAnyVersionChange && !!previousEntry && buildChanged;
+  // With status-reminder-interval-hours, an unchanged status is notified again once the interval has passed
+  const reminderHours = getReminderHours(context, previousEntry, reviewInfo.version, reviewInfo.status);
+
+  const summaryEntry = {
+    appId,
+    platform,
+    version: reviewInfo.version,
+    buildNumber: reviewInfo.buildNumber,
+    status: reviewInfo.status,
+    changed: buildChanged || statusChanged,
+    notified: false,
+  };
+  summary.testFlight.apps.push(summaryEntry);
+
+  await updateIncidents(
+    context,
+    {
+      platform: 'TestFlight',
+      subject: key,
+      version: reviewInfo.version,
+      status: reviewInfo.status,
+      previousStatus: previousEntry?.status,
+    },
+    summaryEntry.changed,
+    recoveredFromRejection
+  );
+
+  if (
+    regressedFromApproval ||
+    anyVersionChange ||
+    reminderHours !== undefined ||
+    ((buildChanged || statusChanged || recoveredFromRejection) && shouldNotify)
+  ) {
+    const previousStatus = previousEntry?.status;
+
+    const payload: NotificationPayload = {
+      platform: 'TestFlight',
+      version: `${reviewInfo.version} (${reviewInfo.buildNumber})${getPlatformSuffix(config, platform)}`,
+      currentStatus: reviewInfo.status,
+      previousStatus: previousStatus || undefined,
+      regression: regressedFromApproval,
+      flapping: flapping,
+      reminderHours: reminderHours,
+      // Looked up by the App Store check of the same app, which runs first
+      appName: currentCache.appStore?.[key]?.appName,
+      consoleUrl: `${APP_STORE_CONNECT_URL}/apps/${appId}/testflight`,
+      event: {
+        appId,
+        version: reviewInfo.version,
+        buildNumber: reviewInfo.buildNumber,
+        changed: summaryEntry.changed,
+        recovered: recoveredFromRejection,
+      },
+    };
+
+    if (!(await sendNotification(context, payload, cacheEntry, reviewInfo.version))) {
+      return false;
+    }
+    summaryEntry.notified = true;
+
+    logEvent(
+      'notification_sent',
+      `Sent TestFlight notification for app ${key} (${previousStatus} -> ${reviewInfo.status}, build ${reviewInfo.buildNumber})`,
+      { ...eventFields, previousStatus, changed: summaryEntry.changed }
+    );
+    return true;
+  }
+
+  const skippedFields = { ...eventFields, previousStatus: previousEntry?.status, changed: summaryEntry.changed };
+  if (!buildChanged && !statusChanged) {
+    logEvent('notification_skipped', `TestFlight build and status for app ${key} have not changed, skipping notification`, skippedFields);
+  } else {
+    logEvent('notification_skipped', `TestFlight status for app ${key} does not require notification`, skippedFields);
+  }
+  return false;
 }
 
-function shouldSendNotification(status: string): boolean {
+// Review outcomes, notified whatever notify-statuses holds
+const REVIEW_SUBMISSION_OUTCOME_STATES: string[] = [ReviewSubmissionState.COMPLETE, ReviewSubmissionState.UNRESOLVED_ISSUES];
+
+/**
+ * Check the state of a single app's latest review submission, which is what moves through
+ * review under the review submission model.
+ * Returns whether a notification was sent.
+ */
+async function monitorReviewSubmission(
+  context: RunContext,
+  fetched: Prefetched<ReviewSubmissionInfo | null>,
+  appId: string,
+  platform: string,
+  isPrimary: boolean
+): Promise<boolean> {
+  const { config, cacheManager, previousCache, currentCache, summary } = context;
+  const submissionInfo = fetched.value;
+  const key = getAppStoreKey(appId, platform);
+
+  if (!submissionInfo) {
+    core.info(`No review submission information available for app ${key}`);
+    summary.reviewSubmissions.apps.push({ appId, platform, changed: false, notified: false });
+    return false;
+  }
+
+  const version = submissionInfo.version || submissionInfo.submissionId;
+  const eventFields = {
+    platform: 'App Store Submission',
+    appId,
+    appPlatform: platform,
+    version,
+    submissionId: submissionInfo.submissionId,
+    status: submissionInfo.state,
+  };
+  logEvent('status_fetched', `Review submission state for app ${key}: ${submissionInfo.state}`, {
+    ...eventFields,
+    durationMs: fetched.durationMs,
+  });
+  core.setOutput(`review-submission-state-${key}`, submissionInfo.state);
+  if (isPrimary) {
+    core.setOutput('review-submission-state', submissionInfo.state);
+  }
+
+  const previousEntry = previousCache?.reviewSubmissions?.[key];
+
+  const history = cacheManager.appendHistory(previousEntry?.history, {
+    status: submissionInfo.state,
+    version: version,
+    timestamp: currentCache.lastChecked,
+  });
+  const flapping =
+    config.cache.flapping && cacheManager.detectFlapping(history, config.cache.flapping, new Date(currentCache.lastChecked))
+      ? config.cache.flapping
+      : undefined;
+
+  // Update current cache
+  const cacheEntry: ReviewSubmissionCacheEntry = {
+    appId: submissionInfo.appId,
+    platform: platform,
+    submissionId: submissionInfo.submissionId,
+    version: version,
+    status: submissionInfo.state,
+    history: history,
+    slackThread: getSlackThread(previousEntry, submissionInfo.submissionId),
+    lastNotification: previousEntry?.lastNotification,
+    slackMessageKey: previousEntry?.slackMessageKey,
+  };
+  currentCache.reviewSubmissions = {
+    ...currentCache.reviewSubmissions,
+    [key]: cacheEntry,
+  };
+
+  // A resubmission is a new submission, even for the same version
+  const submissionChanged = !previousEntry || previousEntry.submissionId !== submissionInfo.submissionId;
+  const statusChanged = !!previousEntry && previousEntry.status !== submissionInfo.state;
+  const shouldNotify =
+    REVIEW_SUBMISSION_OUTCOME_STATES.includes(submissionInfo.state) ||
+    shouldSendNotification(submissionInfo.state, config.notifications);
+  const reminderHours = getReminderHours(context, previousEntry, version, submissionInfo.state);
+
+  const summaryEntry = {
+    appId,
+    platform,
+    version,
+    status: submissionInfo.state,
+    changed: submissionChanged || statusChanged,
+    notified: false,
+  };
+  summary.reviewSubmissions.apps.push(summaryEntry);
+
+  if (reminderHours !== undefined || (summaryEntry.changed && shouldNotify)) {
+    const previousStatus = submissionChanged ? undefined : previousEntry?.status;
+
+    const payload: NotificationPayload = {
+      platform: 'App Store Submission',
+      version: `${version}${getPlatformSuffix(config, platform)}`,
+      currentStatus: submissionInfo.state,
+      previousStatus: previousStatus,
+      flapping: flapping,
+      reminderHours: reminderHours,
+      appName: currentCache.appStore?.[key]?.appName,
+      submittedAt: submissionInfo.submittedDate,
+      consoleUrl: `${APP_STORE_CONNECT_URL}/apps/${appId}/distribution/reviewsubmissions`,
+      event: {
+        appId,
+        version: version,
+        changed: summaryEntry.changed,
+        recovered: false,
+      },
+    };
+
+    if (!(await sendNotification(context, payload, cacheEntry, version))) {
+      return false;
+    }
+    summaryEntry.notified = true;
+
+    logEvent(
+      'notification_sent',
+      `Sent review submission notification for app ${key} (${previousStatus} -> ${submissionInfo.state})`,
+      { ...eventFields, previousStatus, changed: summaryEntry.changed }
+    );
+    return true;
+  }
+
+  const skippedFields = { ...eventFields, previousStatus: previousEntry?.status, changed: summaryEntry.changed };
+  if (!summaryEntry.changed) {
+    logEvent('notification_skipped', `Review submission state for app ${key} has not changed, skipping notification`, skippedFields);
+  } else {
+    logEvent('notification_skipped', `Review submission state for app ${key} does not require notification`, skippedFields);
+  }
+  return false;
+}
+
+// Processing states meaning the upload will never reach review
+const FAILED_PROCESSING_STATES: string[] = [BuildProcessingState.FAILED, BuildProcessingState.INVALID];
+
+/**
+ * Check the processing state of a single app's latest uploaded build, notifying when processing
+ * fails so a broken upload is noticed before anyone waits for review.
+ * Returns whether a notification was sent.
+ */
+async function monitorBuildProcessing(
+  context: RunContext,
+  fetched: Prefetched<BuildProcessingInfo | null>,
+  appId: string,
+  platform: string,
+  isPrimary: boolean
+): Promise<boolean> {
+  const { config, previousCache, currentCache, summary } = context;
+  const buildInfo = fetched.value;
+  const key = getAppStoreKey(appId, platform);
+
+  if (!buildInfo) {
+    core.info(`No build processing information available for app ${key}`);
+    summary.buildProcessing.apps.push({ appId, platform, changed: false, notified: false });
+    return false;
+  }
+
+  const eventFields = {
+    platform: 'App Store Build',
+    appId,
+    appPlatform: platform,
+    version: buildInfo.version,
+    buildNumber: buildInfo.buildNumber,
+    status: buildInfo.processingState,
+  };
+  logEvent(
+    'status_fetched',
+    `Build processing state for app ${key} build ${buildInfo.buildNumber}: ${buildInfo.processingState}`,
+    { ...eventFields, durationMs: fetched.durationMs }
+  );
+  core.setOutput(`build-processing-state-${key}`, buildInfo.processingState);
+  if (isPrimary) {
+    core.setOutput('build-processing-state', buildInfo.processingState);
+  }
+
+  const previousEntry = previousCache?.buildProcessing?.[key];
+
+  // Update current cache
+  const cacheEntry: BuildProcessingCacheEntry = {
+    appId: buildInfo.appId,
+    platform: platform,
+    version: buildInfo.version,
+    buildNumber: buildInfo.buildNumber,
+    processingState: buildInfo.processingState,
+    slackThread: getSlackThread(previousEntry, buildInfo.buildNumber),
+    lastNotification: previousEntry?.lastNotification,
+    slackMessageKey: previousEntry?.slackMessageKey,
+  };
+  currentCache.buildProcessing = {
+    ...currentCache.buildProcessing,
+    [key]: cacheEntry,
+  };
+
+  const sameBuild = previousEntry?.buildNumber === buildInfo.buildNumber;
+  const changed = !sameBuild || previousEntry?.processingState !== buildInfo.processingState;
+
+  const summaryEntry = {
+    appId,
+    platform,
+    version: buildInfo.version,
+    buildNumber: buildInfo.buildNumber,
+    status: buildInfo.processingState,
+    changed: changed,
+    notified: false,
+  };
+  summary.buildProcessing.apps.push(summaryEntry);
+
+  if (changed && FAILED_PROCESSING_STATES.includes(buildInfo.processingState)) {
+    const previousStatus = sameBuild ? previousEntry?.processingState : undefined;
+
+    const payload: NotificationPayload = {
+      platform: 'App Store Build',
+      version: `${buildInfo.version} (${buildInfo.buildNumber})${getPlatformSuffix(config, platform)}`,
+      currentStatus: buildInfo.processingState,
+      previousStatus: previousStatus,
+      appName: currentCache.appStore?.[key]?.appName,
+      consoleUrl: `${APP_STORE_CONNECT_URL}/apps/${appId}/testflight`,
+      event: {
+        appId,
+        version: buildInfo.version,
+        buildNumber: buildInfo.buildNumber,
+        changed: changed,
+        recovered: false,
+      },
+    };
+
+    if (!(await sendNotification(context, payload, cacheEntry, buildInfo.buildNumber))) {
+      return false;
+    }
+    summaryEntry.notified = true;
+
+    logEvent(
+      'notification_sent',
+      `Sent build processing notification for app ${key} (build ${buildInfo.buildNumber}: ${buildInfo.processingState})`,
+      { ...eventFields, previousStatus, changed }
+    );
+    return true;
+  }
+
+  const skippedFields = { ...eventFields, previousStatus: previousEntry?.processingState, changed };
+  if (!changed) {
+    logEvent('notification_skipped', `Build processing state for app ${key} has not changed, skipping notification`, skippedFields);
+  } else {
+    logEvent('notification_skipped', `Build processing state for app ${key} does not require notification`, skippedFields);
+  }
+  return false;
+}
+
+// Product review states worth notifying about when a product moves into them
+const REJECTED_IN_APP_PURCHASE_STATES = ['REJECTED', 'DEVELOPER_ACTION_NEEDED'];
+// Product review states reported in the in-app-purchases-pending output
+const PENDING_IN_APP_PURCHASE_STATES = ['WAITING_FOR_REVIEW', 'IN_REVIEW', ...REJECTED_IN_APP_PURCHASE_STATES];
+
+/**
+ * Check the review state of a single app's in-app purchases and subscriptions, which Apple
+ * reviews separately from the app, notifying when a product is rejected or needs action.
+ * Returns whether a notification was sent.
+ */
+async function monitorInAppPurchases(
+  context: RunContext,
+  fetched: Prefetched<InAppPurchaseInfo[]>,
+  appId: string,
+  isPrimary: boolean
+): Promise<boolean> {
+  const { config, previousCache, currentCache, summary } = context;
+  const products = fetched.value;
+  core.info(`Fetched review states of ${products.length} in-app purchase(s)/subscription(s) for app ${appId}`);
+
+  const pending = products
+    .filter((product) => PENDING_IN_APP_PURCHASE_STATES.includes(product.state))
+    .map((product) => ({ productId: product.productId, kind: product.kind, state: product.state }));
+  core.setOutput(`in-app-purchases-pending-${appId}`, JSON.stringify(pending));
+  if (isPrimary) {
+    core.setOutput('in-app-purchases-pending', JSON.stringify(pending));
+  }
+
+  const appName = currentCache.appStore?.[getAppStoreKey(appId, config.appStore!.platforms[0])]?.appName;
+  let notificationSent = false;
+
+  for (const product of products) {
+    const key = `${appId}/${product.productId}`;
+    const eventFields = {
+      platform: product.kind,
+      appId,
+      productId: product.productId,
+      status: product.state,
+    };
+    logEvent('status_fetched', `${product.kind} ${product.productId} review state for app ${appId}: ${product.state}`, {
+      ...eventFields,
+      durationMs: fetched.durationMs,
+    });
+
+    const previousEntry = previousCache?.inAppPurchases?.[key];
+
+    // Update current cache
+    const cacheEntry: InAppPurchaseCacheEntry = {
+      appId: appId,
+      productId: product.productId,
+      kind: product.kind,
+      state: product.state,
+      slackThread: getSlackThread(previousEntry, product.productId),
+      lastNotification: previousEntry?.lastNotification,
+      slackMessageKey: previousEntry?.slackMessageKey,
+    };
+    currentCache.inAppPurchases = {
+      ...currentCache.inAppPurchases,
+      [key]: cacheEntry,
+    };
+
+    const changed = previousEntry?.state !== product.state;
+
+    const summaryEntry = {
+      appId,
+      productId: product.productId,
+      kind: product.kind,
+      status: product.state,
+      changed: changed,
+      notified: false,
+    };
+    summary.inAppPurchases.products.push(summaryEntry);
+
+    const skippedFields = { ...eventFields, previousStatus: previousEntry?.state, changed };
+    if (!changed) {
+      logEvent('notification_skipped', `${product.kind} ${product.productId} review state has not changed, skipping notification`, skippedFields);
+      continue;
+    }
+    if (!REJECTED_IN_APP_PURCHASE_STATES.includes(product.state)) {
+      logEvent('notification_skipped', `${product.kind} ${product.productId} review state does not require notification`, skippedFields);
+      continue;
+    }
+
+    const payload: NotificationPayload = {
+      platform: product.kind,
+      version: `${product.name} (${product.productId})`,
+      currentStatus: product.state,
+      previousStatus: previousEntry?.state,
+      appName: appName,
+      consoleUrl: `${APP_STORE_CONNECT_URL}/apps/${appId}`,
+      event: {
+        appId,
+        version: product.productId,
+        changed: changed,
+        recovered: false,
+      },
+    };
+
+    if (!(await sendNotification(context, payload, cacheEntry, product.productId))) {
+      continue;
+    }
+    summaryEntry.notified = true;
+    notificationSent = true;
+
+    logEvent(
+      'notification_sent',
+      `Sent ${product.kind} notification for app ${appId} (${product.productId}: ${product.state})`,
+      { ...eventFields, previousStatus: previousEntry?.state, changed }
+    );
+  }
+
+  return notificationSent;
+}
+
+/**
+ * Update the cache entry for a single Google Play track and notify if needed.
+ * Returns whether a notification was sent.
+ */
+async function monitorGooglePlayTrack(
+  context: RunContext,
+  reviewInfo: GooglePlayReviewInfo,
+  isPrimary: boolean,
+  fetchDurationMs: number
+): Promise<boolean> {
+  const { config, cacheManager, previousCache, currentCache, summary } = context;
+  const track = reviewInfo.track;
+
+  const eventFields = {
+    platform: 'Google Play',
+    packageName: reviewInfo.packageName,
+    track,
+    versionCode: reviewInfo.versionCode,
+    status: reviewInfo.status,
+  };
+  // All tracks come from a single edit, so they share its fetch duration
+  logEvent('status_fetched', `Google Play ${track} status: ${reviewInfo.status}`, {
+    ...eventFields,
+    durationMs: fetchDurationMs,
+  });
+  core.setOutput(`google-play-status-${track}`, reviewInfo.status);
+  if (isPrimary) {
+    // Keep the single-track output for backward compatibility
+    core.setOutput('google-play-status', reviewInfo.status);
+  }
+  setDerivedStatusOutputs('google-play', track, reviewInfo.status, isPrimary);
+
+  const previousEntry = previousCache?.googlePlay?.[track];
+
+  const history = cacheManager.appendHistory(previousEntry?.history, {
+    status: reviewInfo.status,
+    version: `${reviewInfo.versionCode}`,
+    timestamp: currentCache.lastChecked,
+  });
+  const flapping =
+    config.cache.flapping && cacheManager.detectFlapping(history, config.cache.flapping, new Date(currentCache.lastChecked))
+      ? config.cache.flapping
+      : undefined;
+
+  const duration = cacheManager.getCurrentStatusDuration(history, new Date(currentCache.lastChecked));
+  if (duration !== undefined) {
+    core.setOutput(`google-play-duration-${track}`, duration);
+    if (isPrimary) {
+      core.setOutput('google-play-duration', duration);
+    }
+  }
+
+  // Update current cache
+  const cacheEntry: GooglePlayCacheEntry = {
+    packageName: reviewInfo.packageName,
+    appName: reviewInfo.appName,
+    track: track,
+    versionCode: reviewInfo.versionCode,
+    versionName: reviewInfo.versionName,
+    status: reviewInfo.status,
+    userFraction: reviewInfo.userFraction,
+    history: history,
+    slackThread: getSlackThread(previousEntry, `${reviewInfo.versionCode}`),
+    lastNotification: previousEntry?.lastNotification,
+    slackMessageKey: previousEntry?.slackMessageKey,
+  };
+  currentCache.googlePlay = {
+    ...currentCache.googlePlay,
+    [track]: cacheEntry,
+  };
+
+  // Check if version has changed
+  const versionChanged = cacheManager.hasVersionOrBuildChanged(
+    'googlePlay',
+    reviewInfo.versionCode,
+    undefined,
+    previousEntry
+  );
+  cacheEntry.rejectionCount = cacheManager.countRejections(reviewInfo.status, versionChanged, previousEntry);
+  cacheEntry.consecutiveUnchangedRuns = cacheManager.countUnchangedRuns(reviewInfo.status, versionChanged, previousEntry);
+  core.setOutput(`google-play-unchanged-runs-${track}`, cacheEntry.consecutiveUnchangedRuns);
+  if (isPrimary) {
+    core.setOutput('google-play-unchanged-runs', cacheEntry.consecutiveUnchangedRuns);
+  }
+
+  // Check if recovered from rejection
+  const recoveredFromRejection = cacheManager.hasRecoveredFromRejection(
+    'googlePlay',
+    reviewInfo.status,
+    previousEntry
+  );
+
+  // Check if regressed from an approved status (always notified, regardless of version change)
+  const regressedFromApproval = cacheManager.hasRegressedFromApproval(
+    'googlePlay',
+    reviewInfo.status,
+    previousEntry
+  );
+
+  const changed = versionChanged || recoveredFromRejection;
+  core.setOutput(`google-play-changed-${track}`, changed);
+  if (isPrimary) {
+    core.setOutput('google-play-changed', changed);
+  }
+
+  // Check if the staged rollout percentage moved within the same version. Caches without
+  // userFraction are skipped so upgrading doesn't report every track as changed.
+  const rolloutChanged =
+    config.notifications.notifyOnRolloutChange &&
+    !versionChanged &&
+    previousEntry?.userFraction !== undefined &&
+    previousEntry.userFraction !== reviewInfo.userFraction;
+
+  // Rollout transitions of the same release, notified even though the version didn't change
+  const rolloutCompleted =
+    !versionChanged &&
+    previousEntry?.status === GooglePlayReviewStatus.IN_PROGRESS &&
+    reviewInfo.status === GooglePlayReviewStatus.COMPLETED;
+  const rolloutHalted =
+    !versionChanged &&
+    !!previousEntry &&
+    previousEntry.status !== GooglePlayReviewStatus.HALTED &&
+    reviewInfo.status === GooglePlayReviewStatus.HALTED;
+
+  // Drafts haven't been sent for review yet, so there's nothing to report
+  const isDraft = reviewInfo.status === GooglePlayReviewStatus.DRAFT;
+
+  // Check if we should notify (status-based check)
+  const shouldNotify = !isDraft && shouldSendNotification(reviewInfo.status, config.notifications);
+  // With notify-on-any-version-change, a new version/build notifies whatever its status
+  // (not on an entry's first check, which has nothing to compare against)
+  const anyVersionChange = config.notifications.notifyOnAnyVersionChange && !!previousEntry && versionChanged;
+  // With status-reminder-interval-hours, an unchanged status is notified again once the interval has passed
+  const reminderHours = getReminderHours(context, previousEntry, `${reviewInfo.versionCode}`, reviewInfo.status);
+
+  const summaryEntry = {
+    track,
+    packageName: reviewInfo.packageName,
+    versionCode: reviewInfo.versionCode,
+    status: reviewInfo.status,
+    changed: versionChanged || cacheManager.hasStatusChanged(reviewInfo.status, previousEntry),
+    notified: false,
+  };
+  summary.googlePlay.tracks.push(summaryEntry);
+
+  await updateIncidents(
+    context,
+    {
+      platform: 'Google Play',
+      subject: `${reviewInfo.packageName} (${track})`,
+      version: `${reviewInfo.versionCode}`,
+      status: reviewInfo.status,
+      previousStatus: previousEntry?.status,
+    },
+    summaryEntry.changed,
+    recoveredFromRejection
+  );
+
+  // With notify-on-transition-to, only a status change into one of its states notifies
+  const transitionMatch = getTransitionMatch(context, reviewInfo.status, previousEntry);
+
+  // Notify if: regressed OR rollout changed/completed/halted OR any version change OR reminder due OR ((version changed OR recovered from rejection) AND should notify)
+  if (
+    !isDraft &&
+    (transitionMatch ??
+      (regressedFromApproval ||
+        anyVersionChange ||
+        reminderHours !== undefined ||
+        rolloutChanged ||
+        rolloutCompleted ||
+        rolloutHalted ||
+        ((versionChanged || recoveredFromRejection) && shouldNotify)))
+  ) {
+    const previousVersionCode = previousEntry?.versionCode;
+    const previousStatus = previousEntry?.status;
+
+    const payload: NotificationPayload = {
+      platform: 'Google Play',
+      version: `${reviewInfo.versionCode} (${track})`,
+      currentStatus: reviewInfo.status,
+      previousStatus: previousStatus || undefined,
+      regression: regressedFromApproval,
+      flapping: flapping,
+      reminderHours: reminderHours,
+      appName: reviewInfo.appName,
+      rejectionReason: reviewInfo.rejectionReason,
+      // A halted rollout shows how far it got before it was stopped
+      rolloutPercentage:
+        (reviewInfo.status === GooglePlayReviewStatus.IN_PROGRESS || reviewInfo.status === GooglePlayReviewStatus.HALTED) &&
+        reviewInfo.userFraction !== undefined
+          ? Math.round(reviewInfo.userFraction * 1000) / 10
+          : undefined,
+      rolloutCompleted: rolloutCompleted,
+      // The Play Console has no stable per-package URL without the developer account ID
+      consoleUrl: GOOGLE_PLAY_CONSOLE_URL,
+      event: {
+        packageName: reviewInfo.packageName,
+        version: reviewInfo.versionName || `${reviewInfo.versionCode}`,
+        versionCode: reviewInfo.versionCode,
+        changed: summaryEntry.changed,
+        recovered: recoveredFromRejection,
+      },
+    };
+
+    if (!(await sendNotification(context, payload, cacheEntry, `${reviewInfo.versionCode}`))) {
+      return false;
+    }
+    summaryEntry.notified = true;
+
+    let reason: string;
+    if (transitionMatch) {
+      reason = `transition to ${reviewInfo.status}: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (regressedFromApproval) {
+      reason = `regressed from approval: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (recoveredFromRejection) {
+      reason = `recovered from rejection: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (rolloutCompleted) {
+      reason = 'rollout completed';
+    } else if (rolloutHalted) {
+      reason = `rollout halted: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (rolloutChanged) {
+      reason = `rollout changed: ${previousEntry?.userFraction} -> ${reviewInfo.userFraction}`;
+    } else if (reminderHours !== undefined) {
+      reason = `reminder: ${reviewInfo.status} unchanged for ${reminderHours} hours`;
+    } else {
+      reason = `version changed: ${previousVersionCode} -> ${reviewInfo.versionCode}`;
+    }
+    logEvent('notification_sent', `Sent Google Play ${track} notification (${reason})`, {
+      ...eventFields,
+      previousStatus,
+      changed: summaryEntry.changed,
+    });
+    return true;
+  }
+
+  const skippedFields = { ...eventFields, previousStatus: previousEntry?.status, changed: summaryEntry.changed };
+  if (isDraft) {
+    logEvent('notification_skipped', `Google Play ${track} release is a draft (not submitted), skipping notification`, skippedFields);
+  } else if (!versionChanged && !recoveredFromRejection) {
+    logEvent(
+      'notification_skipped',
+      `Google Play ${track} version has not changed and not recovered from rejection, skipping notification`,
+      skippedFields
+    );
+  } else {
+    logEvent('notification_skipped', `Google Play ${track} status does not require notification`, skippedFields);
+  }
+  return false;
+}
+
+/**
+ * Check the active edit of the Amazon Appstore app, update its cache entry and notify if needed.
+ * Returns whether a notification was sent.
+ */
+async function monitorAmazonApp(
+  context: RunContext,
+  monitor: AmazonAppstoreMonitor,
+  appId: string
+): Promise<boolean> {
+  const { config, cacheManager, previousCache, currentCache, summary } = context;
+  const startedAt = Date.now();
+  const previousEntry = previousCache?.amazon?.[appId];
+  // A published edit is no longer returned, so its version comes from the cache. An edit that
+  // disappears before it was submitted was deleted rather than published.
+  const submitted =
+    previousEntry?.status === AmazonReviewStatus.SUBMITTED || previousEntry?.status === AmazonReviewStatus.LIVE;
+  const reviewInfo = await monitor.getAmazonReviewStatus(submitted ? previousEntry?.version : undefined);
+
+  if (!reviewInfo) {
+    core.info(`No Amazon Appstore review information available for app ${appId}`);
+    summary.amazon.apps.push({ appId, changed: false, notified: false });
+    return false;
+  }
+
+  const eventFields = {
+    platform: 'Amazon Appstore',
+    appId,
+    version: reviewInfo.version,
+    status: reviewInfo.status,
+  };
+  logEvent('status_fetched', `Amazon Appstore status for app ${appId}: ${reviewInfo.status}`, {
+    ...eventFields,
+    durationMs: Date.now() - startedAt,
+  });
+  core.setOutput('amazon-status', reviewInfo.status);
+
+  const history = cacheManager.appendHistory(previousEntry?.history, {
+    status: reviewInfo.status,
+    version: reviewInfo.version,
+    timestamp: currentCache.lastChecked,
+  });
+  const flapping =
+    config.cache.flapping && cacheManager.detectFlapping(history, config.cache.flapping, new Date(currentCache.lastChecked))
+      ? config.cache.flapping
+      : undefined;
+
+  const duration = cacheManager.getCurrentStatusDuration(history, new Date(currentCache.lastChecked));
+  if (duration !== undefined) {
+    core.setOutput('amazon-duration', duration);
+  }
+
+  // Update current cache
+  const cacheEntry: AmazonCacheEntry = {
+    appId: reviewInfo.appId,
+    editId: reviewInfo.editId,
+    version: reviewInfo.version,
+    status: reviewInfo.status,
+    history: history,
+    slackThread: getSlackThread(previousEntry, reviewInfo.version),
+    lastNotification: previousEntry?.lastNotification,
+    slackMessageKey: previousEntry?.slackMessageKey,
+  };
+  currentCache.amazon = {
+    ...currentCache.amazon,
+    [appId]: cacheEntry,
+  };
+
+  // Check if version has changed
+  const versionChanged = cacheManager.hasVersionOrBuildChanged('amazon', reviewInfo.version, undefined, previousEntry);
+  cacheEntry.rejectionCount = cacheManager.countRejections(reviewInfo.status, versionChanged, previousEntry);
+  cacheEntry.consecutiveUnchangedRuns = cacheManager.countUnchangedRuns(reviewInfo.status, versionChanged, previousEntry);
+  core.setOutput('amazon-unchanged-runs', cacheEntry.consecutiveUnchangedRuns);
+
+  // Check if recovered from rejection
+  const recoveredFromRejection = cacheManager.hasRecoveredFromRejection('amazon', reviewInfo.status, previousEntry);
+
+  // Check if regressed from an approved status (always notified, regardless of version change)
+  const regressedFromApproval = cacheManager.hasRegressedFromApproval('amazon', reviewInfo.status, previousEntry);
+
+  const changed = versionChanged || recoveredFromRejection;
+  core.setOutput('amazon-changed', changed);
+
+  // Submission and publication are status changes of the same version
+  const statusChanged = cacheManager.hasStatusChanged(reviewInfo.status, previousEntry);
+  const enteredReview = config.notifications.notifyOnInReview && isInReviewStatus(reviewInfo.status) && statusChanged;
+  const published =
+    reviewInfo.status === AmazonReviewStatus.LIVE && previousEntry?.status === AmazonReviewStatus.SUBMITTED;
+
+  // Edits that haven't been submitted yet have nothing to report
+  const isDraft = reviewInfo.status === AmazonReviewStatus.IN_PROGRESS;
+
+  // Check if we should notify (status-based check)
+  const shouldNotify = !isDraft && shouldSendNotification(reviewInfo.status, config.notifications);
+  // With notify-on-any-version-change, a new version/build notifies whatever its status
+  // (not on an entry's first check, which has nothing to compare against)
+  const anyVersionChange = config.notifications.notifyOnAnyVersionChange && !!previousEntry && versionChanged;
+  // With status-reminder-interval-hours, an unchanged status is notified again once the interval has passed
+  const reminderHours = getReminderHours(context, previousEntry, reviewInfo.version, reviewInfo.status);
+
+  const summaryEntry = {
+    appId,
+    version: reviewInfo.version,
+    status: reviewInfo.status,
+    changed: versionChanged || statusChanged,
+    notified: false,
+  };
+  summary.amazon.apps.push(summaryEntry);
+
+  await updateIncidents(
+    context,
+    {
+      platform: 'Amazon Appstore',
+      subject: appId,
+      version: reviewInfo.version,
+      status: reviewInfo.status,
+      previousStatus: previousEntry?.status,
+    },
+    summaryEntry.changed,
+    recoveredFromRejection
+  );
+
+  // With notify-on-transition-to, only a status change into one of its states notifies
+  const transitionMatch = getTransitionMatch(context, reviewInfo.status, previousEntry);
+
+  // Notify if: regressed OR any version change OR reminder due OR ((version changed OR recovered from rejection OR entered review OR published) AND should notify)
+  if (
+    !isDraft &&
+    (transitionMatch ??
+      (regressedFromApproval ||
+        anyVersionChange ||
+        reminderHours !== undefined ||
+        ((versionChanged || recoveredFromRejection || enteredReview || published) && shouldNotify)))
+  ) {
+    const previousVersion = previousEntry?.version;
+    const previousStatus = previousEntry?.status;
+
+    const payload: NotificationPayload = {
+      platform: 'Amazon Appstore',
+      version: reviewInfo.version,
+      currentStatus: reviewInfo.status,
+      previousStatus: previousStatus || undefined,
+      regression: regressedFromApproval,
+      flapping: flapping,
+      reminderHours: reminderHours,
+      consoleUrl: AMAZON_DEVELOPER_CONSOLE_URL,
+      event: {
+        appId,
+        version: reviewInfo.version,
+        changed: summaryEntry.changed,
+        recovered: recoveredFromRejection,
+      },
+    };
+
+    if (!(await sendNotification(context, payload, cacheEntry, reviewInfo.version))) {
+      return false;
+    }
+    summaryEntry.notified = true;
+
+    let reason: string;
+    if (transitionMatch) {
+      reason = `transition to ${reviewInfo.status}: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (regressedFromApproval) {
+      reason = `regressed from approval: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (recoveredFromRejection) {
+      reason = `recovered from rejection: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if ((enteredReview || published) && !versionChanged) {
+      reason = `review status changed: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (reminderHours !== undefined) {
+      reason = `reminder: ${reviewInfo.status} unchanged for ${reminderHours} hours`;
+    } else {
+      reason = `version changed: ${previousVersion} -> ${reviewInfo.version}`;
+    }
+    logEvent('notification_sent', `Sent Amazon Appstore notification for app ${appId} (${reason})`, {
+      ...eventFields,
+      previousStatus,
+      changed: summaryEntry.changed,
+    });
+    return true;
+  }
+
+  const skippedFields = { ...eventFields, previousStatus: previousEntry?.status, changed: summaryEntry.changed };
+  if (isDraft) {
+    logEvent('notification_skipped', `Amazon Appstore edit for app ${appId} hasn't been submitted, skipping notification`, skippedFields);
+  } else if (!versionChanged && !recoveredFromRejection && !enteredReview && !published) {
+    logEvent(
+      'notification_skipped',
+      `Amazon Appstore version for app ${appId} has not changed and not recovered from rejection, skipping notification`,
+      skippedFields
+    );
+  } else {
+    logEvent('notification_skipped', `Amazon Appstore status for app ${appId} does not require notification`, skippedFields);
+  }
+  return false;
+}
+
+/**
+ * Check the latest version of the Huawei AppGallery app, update its cache entry and notify if needed.
+ * Returns whether a notification was sent.
+ */
+async function monitorHuaweiApp(
+  context: RunContext,
+  monitor: HuaweiAppGalleryMonitor,
+  appId: string
+): Promise<boolean> {
+  const { config, cacheManager, previousCache, currentCache, summary } = context;
+  const startedAt = Date.now();
+  const reviewInfo = await monitor.getHuaweiReviewStatus();
+
+  if (!reviewInfo) {
+    core.info(`No Huawei AppGallery review information available for app ${appId}`);
+    summary.huawei.apps.push({ appId, changed: false, notified: false });
+    return false;
+  }
+
+  const eventFields = {
+    platform: 'Huawei AppGallery',
+    appId,
+    version: reviewInfo.version,
+    status: reviewInfo.status,
+  };
+  logEvent('status_fetched', `Huawei AppGallery status for app ${appId}: ${reviewInfo.status}`, {
+    ...eventFields,
+    durationMs: Date.now() - startedAt,
+  });
+  core.setOutput('huawei-status', reviewInfo.status);
+
+  const previousEntry = previousCache?.huawei?.[appId];
+
+  const history = cacheManager.appendHistory(previousEntry?.history, {
+    status: reviewInfo.status,
+    version: reviewInfo.version,
+    timestamp: currentCache.lastChecked,
+  });
+  const flapping =
+    config.cache.flapping && cacheManager.detectFlapping(history, config.cache.flapping, new Date(currentCache.lastChecked))
+      ? config.cache.flapping
+      : undefined;
+
+  const duration = cacheManager.getCurrentStatusDuration(history, new Date(currentCache.lastChecked));
+  if (duration !== undefined) {
+    core.setOutput('huawei-duration', duration);
+  }
+
+  // Update current cache
+  const cacheEntry: HuaweiCacheEntry = {
+    appId: reviewInfo.appId,
+    appName: reviewInfo.appName,
+    version: reviewInfo.version,
+    status: reviewInfo.status,
+    history: history,
+    slackThread: getSlackThread(previousEntry, reviewInfo.version),
+    lastNotification: previousEntry?.lastNotification,
+    slackMessageKey: previousEntry?.slackMessageKey,
+  };
+  currentCache.huawei = {
+    ...currentCache.huawei,
+    [appId]: cacheEntry,
+  };
+
+  // Check if version has changed
+  const versionChanged = cacheManager.hasVersionOrBuildChanged('huawei', reviewInfo.version, undefined, previousEntry);
+  cacheEntry.rejectionCount = cacheManager.countRejections(reviewInfo.status, versionChanged, previousEntry);
+  cacheEntry.consecutiveUnchangedRuns = cacheManager.countUnchangedRuns(reviewInfo.status, versionChanged, previousEntry);
+  core.setOutput('huawei-unchanged-runs', cacheEntry.consecutiveUnchangedRuns);
+
+  // Check if recovered from rejection
+  const recoveredFromRejection = cacheManager.hasRecoveredFromRejection('huawei', reviewInfo.status, previousEntry);
+
+  // Check if regressed from an approved status (always notified, regardless of version change)
+  const regressedFromApproval = cacheManager.hasRegressedFromApproval('huawei', reviewInfo.status, previousEntry);
+
+  const changed = versionChanged || recoveredFromRejection;
+  core.setOutput('huawei-changed', changed);
+
+  // Review decisions arrive as status changes of the same version (IN_REVIEW -> RELEASED)
+  const statusChanged = cacheManager.hasStatusChanged(reviewInfo.status, previousEntry);
+
+  // Drafts haven't been submitted for review yet, so there's nothing to report
+  const isDraft = reviewInfo.status === HuaweiReviewStatus.DRAFT;
+
+  // Check if we should notify (status-based check)
+  const shouldNotify = !isDraft && shouldSendNotification(reviewInfo.status, config.notifications);
+  // With notify-on-any-version-change, a new version/build notifies whatever its status
+  // (not on an entry's first check, which has nothing to compare against)
+  const anyVersionChange = config.notifications.notifyOnAnyVersionChange && !!previousEntry && versionChanged;
+  // With status-reminder-interval-hours, an unchanged status is notified again once the interval has passed
+  const reminderHours = getReminderHours(context, previousEntry, reviewInfo.version, reviewInfo.status);
+
+  const summaryEntry = {
+    appId,
+    version: reviewInfo.version,
+    status: reviewInfo.status,
+    changed: versionChanged || statusChanged,
+    notified: false,
+  };
+  summary.huawei.apps.push(summaryEntry);
+
+  await updateIncidents(
+    context,
+    {
+      platform: 'Huawei AppGallery',
+      subject: appId,
+      version: reviewInfo.version,
+      status: reviewInfo.status,
+      previousStatus: previousEntry?.status,
+    },
+    summaryEntry.changed,
+    recoveredFromRejection
+  );
+
+  // With notify-on-transition-to, only a status change into one of its states notifies
+  const transitionMatch = getTransitionMatch(context, reviewInfo.status, previousEntry);
+
+  // Notify if: regressed OR any version change OR reminder due OR ((version changed OR recovered from rejection OR status changed) AND should notify)
+  if (
+    !isDraft &&
+    (transitionMatch ??
+      (regressedFromApproval ||
+        anyVersionChange ||
+        reminderHours !== undefined ||
+        ((versionChanged || recoveredFromRejection || statusChanged) && shouldNotify)))
+  ) {
+    const previousVersion = previousEntry?.version;
+    const previousStatus = previousEntry?.status;
+
+    const payload: NotificationPayload = {
+      platform: 'Huawei AppGallery',
+      version: reviewInfo.version,
+      currentStatus: reviewInfo.status,
+      previousStatus: previousStatus || undefined,
+      rejectionReason: cacheManager.isRejectedStatus(reviewInfo.status) ? reviewInfo.rejectionReason : undefined,
+      regression: regressedFromApproval,
+      flapping: flapping,
+      reminderHours: reminderHours,
+      appName: reviewInfo.appName,
+      consoleUrl: APPGALLERY_CONNECT_URL,
+      event: {
+        appId,
+        version: reviewInfo.version,
+        changed: summaryEntry.changed,
+        recovered: recoveredFromRejection,
+      },
+    };
+
+    if (!(await sendNotification(context, payload, cacheEntry, reviewInfo.version))) {
+      return false;
+    }
+    summaryEntry.notified = true;
+
+    let reason: string;
+    if (transitionMatch) {
+      reason = `transition to ${reviewInfo.status}: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (regressedFromApproval) {
+      reason = `regressed from approval: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (recoveredFromRejection) {
+      reason = `recovered from rejection: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (statusChanged && !versionChanged) {
+      reason = `review status changed: ${previousStatus} -> ${reviewInfo.status}`;
+    } else if (reminderHours !== undefined) {
+      reason = `reminder: ${reviewInfo.status} unchanged for ${reminderHours} hours`;
+    } else {
+      reason = `version changed: ${previousVersion} -> ${reviewInfo.version}`;
+    }
+    logEvent('notification_sent', `Sent Huawei AppGallery notification for app ${appId} (${reason})`, {
+      ...eventFields,
+      previousStatus,
+      changed: summaryEntry.changed,
+    });
+    return true;
+  }
+
+  const skippedFields = { ...eventFields, previousStatus: previousEntry?.status, changed: summaryEntry.changed };
+  if (isDraft) {
+    logEvent('notification_skipped', `Huawei AppGallery version for app ${appId} is a draft, skipping notification`, skippedFields);
+  } else if (!versionChanged && !recoveredFromRejection && !statusChanged) {
+    logEvent(
+      'notification_skipped',
+      `Huawei AppGallery version and status for app ${appId} have not changed, skipping notification`,
+      skippedFields
+    );
+  } else {
+    logEvent('notification_skipped', `Huawei AppGallery status for app ${appId} does not require notification`, skippedFields);
+  }
+  return false;
+}
+
+/**
+ * Send a notification through every channel, noting when monitoring may have lapsed.
+ * Slack replies in the version's thread when one exists, and a newly started thread
+ * is stored on the cache entry for later updates of the same version.
+ * Returns false when the notification was suppressed by the cooldown or a first-run baseline,
+ * or held until quiet hours end.
+ */
+async function sendNotification(
+  context: RunContext,
+  payload: NotificationPayload,
+  cacheEntry: NotifiableCacheEntry,
+  version: string
+): Promise<boolean> {
+  const now = context.currentCache.lastChecked;
+
+  if (context.baselineOnly) {
+    core.info(`Skipping ${payload.platform} notification for ${version}: first run records the baseline only`);
+    return false;
+  }
+
+  // Throttle re-notifications of the same status, e.g. when a flaky response briefly reverted it
+  const cooldownMinutes = context.config.notifications.cooldownMinutes;
+  const last = cacheEntry.lastNotification;
+  if (
+    cooldownMinutes > 0 &&
+    last &&
+    last.status === payload.currentStatus &&
+    last.version === version &&
+    Date.parse(now) - Date.parse(last.timestamp) < cooldownMinutes * 60 * 1000
+  ) {
+    logEvent(
+      'notification_suppressed',
+      `Suppressing ${payload.platform} notification: ${payload.currentStatus} for ${version} was already notified at ${last.timestamp} (cooldown: ${cooldownMinutes} minutes)`,
+      { platform: payload.platform, version, status: payload.currentStatus, lastNotifiedAt: last.timestamp }
+    );
+    return false;
+  }
+
+  // Rejections and regressions go out immediately unless quiet-hours-allow-critical is disabled
+  const critical = payload.regression || getStatusColor(payload.currentStatus) === 'danger';
+  if (context.inQuietHours && !(critical && context.config.notifications.quietHours?.allowCritical)) {
+    context.currentCache.heldNotifications = [...(context.currentCache.heldNotifications || []), payload];
+    logEvent('notification_held', `Holding ${payload.platform} notification for ${version} until quiet hours end`, {
+      platform: payload.platform,
+      version,
+      status: payload.currentStatus,
+    });
+    return false;
+  }
+
+  const receipt = await context.notifier.sendNotification({
+    ...payload,
+    monitoringLapsedHours: context.monitoringLapsedHours,
+    slackThreadTs: cacheEntry.slackThread?.ts,
+    // A reminder repeats the last message on purpose
+    slackLastMessageKey: payload.reminderHours === undefined ? cacheEntry.slackMessageKey : undefined,
+  });
+
+  // Updating in place posts a fresh message when the previous one was deleted
+  if (receipt?.slackThreadTs && receipt.slackThreadTs !== cacheEntry.slackThread?.ts) {
+    cacheEntry.slackThread = { version: version, ts: receipt.slackThreadTs };
+  }
+  if (receipt?.slackMessageKey) {
+    cacheEntry.slackMessageKey = receipt.slackMessageKey;
+  }
+  cacheEntry.lastNotification = { status: payload.currentStatus, version: version, timestamp: now };
+  return true;
+}
+
+/**
+ * Hours since the status was last notified, when status-reminder-interval-hours is set, the
+ * same version and status are still current and the interval has passed. Critical statuses are
+ * notified as they happen and approved ones are final, so only the statuses still waiting on the
+ * store or a manual release are reminded.
+ */
+function getReminderHours(
+  context: RunContext,
+  previousEntry: NotifiableCacheEntry | undefined,
+  version: string,
+  status: string
+): number | undefined {
+  const intervalHours = context.config.notifications.statusReminderIntervalHours;
+  const last = previousEntry?.lastNotification;
+  if (intervalHours <= 0 || !last || last.version !== version || last.status !== status) {
+    return undefined;
+  }
+
+  const color = getStatusColor(status);
+  if (color === 'danger' || (color === 'good' && !isActionRequiredStatus(status))) {
+    return undefined;
+  }
+
+  const hours = Math.floor((Date.parse(context.currentCache.lastChecked) - Date.parse(last.timestamp)) / (60 * 60 * 1000));
+  return hours >= intervalHours ? hours : undefined;
+}
+
+/**
+ * Keep the previous Slack thread only while the version stays the same
+ */
+function getSlackThread(
+  previousEntry: NotifiableCacheEntry | undefined,
+  version: string
+): SlackThreadEntry | undefined {
+  return previousEntry?.slackThread?.version === version ? previousEntry.slackThread : undefined;
+}
+
+// App Store states before Apple's review decision
+const AWAITING_DECISION_STATUSES = ['WAITING_FOR_REVIEW', 'IN_REVIEW'];
+
+/**
+ * Whole days since the version was submitted, while it's in review or in the run its review
+ * was decided (so an approval shows how long review took). Undefined otherwise, since the
+ * count would keep growing long after the decision.
+ */
+function getDaysInReview(
+  submittedDate: string | undefined,
+  status: string,
+  previousStatus: string | undefined,
+  now: string
+): number | undefined {
+  const submittedAt = submittedDate ? Date.parse(submittedDate) : NaN;
+  if (
+    Number.isNaN(submittedAt) ||
+    (!AWAITING_DECISION_STATUSES.includes(status) &&
+      !(previousStatus && AWAITING_DECISION_STATUSES.includes(previousStatus)))
+  ) {
+    return undefined;
+  }
+  return Math.max(0, Math.floor((Date.parse(now) - submittedAt) / (24 * 60 * 60 * 1000)));
+}
+
+const APP_STORE_CONNECT_URL = 'https://appstoreconnect.apple.com';
+const APP_STORE_URL = 'https://apps.apple.com';
+const GOOGLE_PLAY_CONSOLE_URL = 'https://play.google.com/console';
+const AMAZON_DEVELOPER_CONSOLE_URL = 'https://developer.amazon.com/apps-and-games/console/apps/list.html';
+const APPGALLERY_CONNECT_URL = 'https://developer.huawei.com/consumer/en/service/josp/agc/index.html';
+
+const APP_STORE_PLATFORM_LABELS: Record<string, string> = {
+  IOS: 'iOS',
+  MAC_OS: 'macOS',
+  TV_OS: 'tvOS',
+  VISION_OS: 'visionOS',
+};
+
+/**
+ * Cache and output key for an App Store app on one platform. iOS keeps the bare app ID
+ * so caches and outputs from before multi-platform support still match.
+ */
+function getAppStoreKey(appId: string, platform: string): string {
+  return platform === 'IOS' ? appId : `${appId}-${platform}`;
+}
+
+/**
+ * Platform shown next to the version in notifications, omitted for iOS-only monitoring
+ */
+function getPlatformSuffix(config: MonitorConfig, platform: string): string {
+  if (platform === 'IOS' && config.appStore?.platforms.length === 1) {
+    return '';
+  }
+  return ` · ${APP_STORE_PLATFORM_LABELS[platform] || platform}`;
+}
+
+/**
+ * Set <store>-status-formatted and <store>-status-category (good, warning, danger or info) next
+ * to the raw status, so later steps can branch without re-implementing the classification
+ */
+function setDerivedStatusOutputs(store: string, key: string, status: string, isPrimary: boolean): void {
+  core.setOutput(`${store}-status-formatted-${key}`, formatStatus(status));
+  core.setOutput(`${store}-status-category-${key}`, getStatusCategory(status));
+  if (isPrimary) {
+    core.setOutput(`${store}-status-formatted`, formatStatus(status));
+    core.setOutput(`${store}-status-category`, getStatusCategory(status));
+  }
+}
+
+/**
+ * Open a PagerDuty incident when an app/track becomes rejected, and an OpsGenie alert when it
+ * enters any danger status (P1 for rejections). The incident is resolved once it recovers from
+ * the rejection, and the alert once it leaves the danger statuses. Failures are logged without affecting the regular notifications.
+ */
+async function updateIncidents(
+  context: RunContext,
+  incident: Incident,
+  changed: boolean,
+  recoveredFromRejection: boolean
+): Promise<void> {
+  if (context.baselineOnly) {
+    return;
+  }
+
+  const rejected = context.cacheManager.isRejectedStatus(incident.status);
+
+  if (context.pagerDuty) {
+    try {
+      if (recoveredFromRejection) {
+        await context.pagerDuty.resolvePagerDutyEvent(incident);
+      } else if (changed && rejected) {
+        await context.pagerDuty.triggerPagerDutyEvent(incident);
+      }
+    } catch (error) {
+      core.warning(`Failed to update PagerDuty incident for ${incident.platform} ${incident.subject}: ${redact(error)}`);
+    }
+  }
+
+  if (context.opsGenie) {
+    const danger = getStatusColor(incident.status) === 'danger';
+    // Alerts are also opened for failures recovery from rejection doesn't cover, such as an
+    // invalid binary, so any move out of a danger status closes them
+    const leftDanger =
+      incident.previousStatus !== undefined && getStatusColor(incident.previousStatus) === 'danger' && !danger;
+    try {
+      if (leftDanger) {
+        await context.opsGenie.closeOpsGenieAlert(incident);
+      } else if (changed && danger) {
+        await context.opsGenie.createOpsGenieAlert(incident, rejected ? 'P1' : 'P2');
+      }
+    } catch (error) {
+      core.warning(`Failed to update OpsGenie alert for ${incident.platform} ${incident.subject}: ${redact(error)}`);
+    }
+  }
+}
+
+// Statuses that indicate a version has entered (or is about to enter) review
+const IN_REVIEW_STATUSES = [
+  'waiting_for_review',
+  'in_review',
+  'processing_for_app_store',
+  'waiting_for_beta_review',
+  'in_beta_review',
+  // Amazon Appstore
+  'submitted',
+];
+
+function isInReviewStatus(status: string): boolean {
   const statusLower = status.toLowerCase();
+  return IN_REVIEW_STATUSES.some((s) => statusLower.includes(s));
+}
+
+// Statuses notified on by default, matched as case-insensitive substrings
+const DEFAULT_NOTIFY_STATUSES = [
+  'pending_developer_release',
+  'pending_apple_release',
+  'ready_for_sale',
+  'rejected',
+  'metadata_rejected',
+  'invalid_binary',
+  'developer_rejected',
+  'removed_from_sale',
+  'halted',
+  'completed',
+  // TestFlight beta review
+  'approved',
+  // Amazon Appstore
+  'live',
+  // Huawei AppGallery
+  'released',
+];
+
+/**
+ * Whether the status just changed into a notify-on-transition-to state (case-insensitive exact
+ * match). Undefined when notify-on-transition-to isn't set, leaving the usual notify conditions.
+ */
+function getTransitionMatch(
+  context: RunContext,
+  status: string,
+  previousEntry: { status: string } | undefined
+): boolean | undefined {
+  const targets = context.config.notifications.notifyOnTransitionTo;
+  if (!targets) {
+    return undefined;
+  }
+  return !!previousEntry && previousEntry.status !== status && targets.includes(status.toLowerCase());
+}
+
+function shouldSendNotification(status: string, notificationConfig: NotificationConfig): boolean {
+  const statusLower = status.toLowerCase();
+
+  // Someone has to act on these, so notify-statuses can't filter them out
+  if (isActionRequiredStatus(status)) {
+    return true;
+  }
 
   // Notify on these statuses
   const notifyStatuses = [
-    'pending_developer_release',
-    'pending_apple_release',
-    'ready_for_sale',
-    'rejected',
-    'metadata_rejected',
-    'invalid_binary',
-    'completed',
+    ...(notificationConfig.notifyStatuses || DEFAULT_NOTIFY_STATUSES),
+    ...(notificationConfig.notifyOnInReview ? IN_REVIEW_STATUSES : []),
   ];
 
   return notifyStatuses.some((s) => statusLower.includes(s.toLowerCase()));
@@ -0,0 +1,150 @@
+import * as core from '@actions/core';
+import axios, { AxiosError } from 'axios';
+import { AmazonConfig, AmazonReviewInfo, AmazonReviewStatus } from '../types';
+import { ApiErrorKind, getHttpErrorKind, StoreApiError } from '../utils/apiError';
+import { HttpRequester } from '../utils/http';
+import { registerSecret } from '../utils/redact';
+
+const TOKEN_URL = 'https://api.amazon.com/auth/o2/token';
+const TOKEN_SCOPE = 'appstore::apps:readwrite';
+
+/**
+ * Amazon Appstore Submission API failure, with the API's message when it returned one
+ */
+export class AmazonApiError extends StoreApiError {
+  constructor(message: string, kind: ApiErrorKind, status?: number) {
+    super(message, kind, status);
+    this.name = 'AmazonApiError';
+  }
+}
+
+export class AmazonAppstoreMonitor {
+  private config: AmazonConfig;
+  private http: HttpRequester;
+  private baseURL = 'https://developer.amazonapi.com/api/appstore/v1';
+
+  constructor(config: AmazonConfig, http: HttpRequester) {
+    this.config = config;
+    this.http = http;
+  }
+
+  /**
+   * Get the review status of the app's active edit. The API has no review history, so
+   * without an active edit the last submitted one is taken to be live, with the version
+   * given as submittedVersion. Returns undefined when there is neither.
+   */
+  async getAmazonReviewStatus(submittedVersion?: string): Promise<AmazonReviewInfo | undefined> {
+    try {
+      const accessToken = await this.getAccessToken();
+      const headers = { Authorization: `Bearer ${accessToken}` };
+      const appUrl = `${this.baseURL}/applications/${encodeURIComponent(this.config.appId)}`;
+
+      // Answers with an empty body when no edit is open
+      const editResponse = await this.http.request({ method: 'get', url: `${appUrl}/edits`, headers });
+      const edit = editResponse.data;
+
+      if (!edit?.id) {
+        if (!submittedVersion) {
+          console.log('No active Amazon Appstore edit found');
+          return undefined;
+        }
+        return {
+          appId: this.config.appId,
+          version: submittedVersion,
+          status: AmazonReviewStatus.LIVE,
+        };
+      }
+
+      const apksResponse = await this.http.request({
+        method: 'get',
+        url: `${appUrl}/edits/${encodeURIComponent(edit.id)}/apks`,
+        headers,
+      });
+      const versionCode = apksResponse.data?.[0]?.versionCode;
+
+      return {
+        appId: this.config.appId,
+        editId: edit.id,
+        // Edits without an APK yet (listing-only changes) are identified by the edit ID
+        version: versionCode !== undefined ? `${versionCode}` : edit.id,
+        status: this.mapStatus(edit.status),
+      };
+    } catch (error) {
+      if (axios.isAxiosError(error)) {
+        console.error('Amazon Appstore API Error:', error.response?.data || error.message);
+        throw toAmazonApiError(error);
+      }
+      console.error('Error fetching Amazon Appstore review status:', error);
+      throw error;
+    }
+  }
+
+  /**
+   * Exchange the client credentials for an access token without reading the app.
+   * Returns the client ID.
+   */
+  async checkCredentials(): Promise<string> {
+    try {
+      await this.getAccessToken();
+    } catch (error) {
+      throw axios.isAxiosError(error) ? toAmazonApiError(error) : error;
+    }
+    return this.config.clientId;
+  }
+
+  private async getAccessToken(): Promise<string> {
+    const response = await this.http.request({
+      method: 'post',
+      url: TOKEN_URL,
+      data: new URLSearchParams({
+        grant_type: 'client_credentials',
+        client_id: this.config.clientId,
+        client_secret: this.config.clientSecret,
+        scope: TOKEN_SCOPE,
+      }).toString(),
+      headers: {
+        'Content-Type': 'application/x-www-form-urlencoded',
+      },
+    });
+
+    // Masked in the log, since request errors carry it in their Authorization header
+    registerSecret(response.data.access_token);
+    return response.data.access_token;
+  }
+
+  private mapStatus(status: string | undefined): AmazonReviewStatus {
+    switch (status) {
+      case 'IN_PROGRESS':
+        return AmazonReviewStatus.IN_PROGRESS;
+      case 'SUBMITTED':
+        return AmazonReviewStatus.SUBMITTED;
+      default:
+        core.warning(`Unknown Amazon Appstore edit status "${status}", treating it as in progress`);
+        return AmazonReviewStatus.IN_PROGRESS;
+    }
+  }
+}
+
+/**
+ * Describe a Submission API or token endpoint error response (after HttpClient has exhausted its retries)
+ */
+export function toAmazonApiError(error: AxiosError): AmazonApiError {
+  const status = error.response?.status;
+  const data: any = error.response?.data;
+  const detail = data?.message || data?.error_description || data?.error || error.message;
+
+  // The token endpoint answers 400/401 invalid_client for wrong or revoked security profiles
+  if (status === 401 || status === 403 || data?.error === 'invalid_client') {
+    return new AmazonApiError(
+      `Amazon Appstore API authentication failed (HTTP ${status}): ${detail}. Check the client ID and secret and the security profile's API access`,
+      'auth',
+      status
+    );
+  }
+
+  return new AmazonApiError(
+    `Amazon Appstore API request failed (HTTP ${status ?? 'no response'}): ${detail}`,
+    getHttpErrorKind(status),
+    status
+  );
+}
@@ -1,50 +1,131 @@
-import axios from 'axios';
+import * as core from '@actions/core';
+import axios, { AxiosError, AxiosRequestConfig, AxiosResponse } from 'axios';
 import * as jwt from 'jsonwebtoken';
-import { AppStoreConfig, AppStoreReviewInfo, AppStoreReviewStatus } from '../types';
+import {
+  AppStoreAppInfo,
+  AppStoreConfig,
+  BuildProcessingInfo,
+  BuildProcessingState,
+  AppStoreReviewInfo,
+  InAppPurchaseInfo,
+  InAppPurchaseKind,
+  AppStoreReviewStatus,
+  PhasedReleaseInfo,
+  ReviewSubmissionInfo,
+  ReviewSubmissionState,
+  TestFlightReviewInfo,
+  TestFlightReviewStatus,
+} from '../types';
+import { ApiErrorKind, getHttpErrorKind, StoreApiError } from '../utils/apiError';
+import { parseAppStorePrivateKey } from '../utils/credentials';
+import { HttpRequester } from '../utils/http';
+import { redact, registerSecret } from '../utils/redact';
+
+const DEFAULT_API_BASE_URL = 'https://api.appstoreconnect.apple.com';
+
+const VERSIONS_PAGE_LIMIT = 50;
+// Recent submissions searched for the selected version
+const REVIEW_SUBMISSIONS_PAGE_LIMIT = 20;
+// Products beyond the first page aren't checked
+const IN_APP_PURCHASES_PAGE_LIMIT = 200;
+// Maximum number of related subscriptions included per subscription group
+const INCLUDED_SUBSCRIPTIONS_LIMIT = 50;
+const APPS_PAGE_LIMIT = 200;
+// Drafts stay READY_FOR_REVIEW until they're submitted
+const SUBMITTED_REVIEW_SUBMISSION_STATES = [
+  ReviewSubmissionState.WAITING_FOR_REVIEW,
+  ReviewSubmissionState.IN_REVIEW,
+  ReviewSubmissionState.UNRESOLVED_ISSUES,
+  ReviewSubmissionState.CANCELING,
+  ReviewSubmissionState.COMPLETING,
+  ReviewSubmissionState.COMPLETE,
+];
+
+// Apple rejects tokens living longer than 20 minutes. iat is backdated so a runner clock
+// slightly ahead of Apple's doesn't produce a not-yet-valid token, and exp keeps a margin
+// so one slightly behind doesn't push the lifetime past the limit.
+const TOKEN_MAX_LIFETIME_SECONDS = 20 * 60;
+const TOKEN_BACKDATE_SECONDS = 30;
+const TOKEN_SAFETY_MARGIN_SECONDS = 60;
+
+/**
+ * Pick the version whose state comes first in the priority list, taking the newest one among
+ * versions in the same state. Falls back to the newest version when no state matches, so the
+ * choice only depends on the versions and their states and doesn't flap between runs.
+ */
+function selectVersion(versions: any[], statePriority: string[]): any {
+  for (const state of statePriority) {
+    const match = versions.find((version) => version.attributes?.appStoreState === state);
+    if (match) {
+      return match;
+    }
+  }
+  return versions[0];
+}
+
+/**
+ * App Store Connect API failure, with the first error of the response's errors array
+ */
+export class AppStoreApiError extends StoreApiError {
+  constructor(message: string, kind: ApiErrorKind, status?: number) {
+    super(message, kind, status);
+    this.name = 'AppStoreApiError';
+  }
+}
 
 export class AppStoreConnectMonitor {
   private config: AppStoreConfig;
-  private baseURL = 'https://api.appstoreconnect.apple.com/v1';
+  private http: HttpRequester;
+  private baseURL: string;
+  private versionFilter?: RegExp;
+  private token?: { value: string; expiresAt: number };
+  // Index into config.keys of the key tokens are signed with
+  private keyIndex = 0;
+  private keyAuthenticated = false;
 
-  constructor(config: AppStoreConfig) {
+  constructor(config: AppStoreConfig, http: HttpRequester) {
     this.config = config;
+    this.http = http;
+    this.baseURL = `${config.apiBaseUrl || DEFAULT_API_BASE_URL}/v1`;
+    this.versionFilter = config.versionFilter ? new RegExp(config.versionFilter) : undefined;
   }
 
-  async getReviewStatus(): Promise<AppStoreReviewInfo | null> {
+  /**
+   * Get the review state of the app's preferred version. The app name is only fetched when
+   * no cachedAppName is given.
+   */
+  async getReviewStatus(appId: string, platform: string, cachedAppName?: string): Promise<AppStoreReviewInfo | null> {
     try {
-      const token = this.generateToken();
-
-      // Get app information
-      const appResponse = await axios.get(
-        `${this.baseURL}/apps/${this.config.appId}`,
-        {
-          headers: {
-            Authorization: `Bearer ${token}`,
-          },
-        }
-      );
+      const appName = cachedAppName ?? (await this.getAppName(appId));
 
-      // Get the latest app store version
-      const versionsResponse = await axios.get(
-        `${this.baseURL}/apps/${this.config.appId}/appStoreVersions`,
-        {
-          headers: {
-            Authorization: `Bearer ${token}`,
-          },
-          params: {
-            'filter[platform]': 'IOS',
-            'limit': 1,
-            'sort': '-createdDate',
-          },
-        }
-      );
+      // Get the first page of App Store versions, newest first
+      const versionsResponse = await this.request({
+        method: 'get',
+        url: `${this.baseURL}/apps/${appId}/appStoreVersions`,
+        params: {
+          'filter[platform]': platform,
+          // Phased release progress is a related resource that only exists when enabled
+          'include': 'appStoreVersionPhasedRelease',
+          'limit': VERSIONS_PAGE_LIMIT,
+          'sort': '-createdDate',
+        },
+      });
 
       if (!versionsResponse.data.data || versionsResponse.data.data.length === 0) {
-        console.log('No app store versions found');
+        console.log(`No ${platform} app store versions found for app ${appId}`);
         return null;
       }
 
-      const latestVersion = versionsResponse.data.data[0];
+      // Older versions Apple still lists can be left out with app-store-version-filter
+      const versions: any[] = this.versionFilter
+        ? versionsResponse.data.data.filter((version: any) => this.versionFilter!.test(version.attributes?.versionString || ''))
+        : versionsResponse.data.data;
+      if (versions.length === 0) {
+        console.log(`No ${platform} app store versions of app ${appId} match app-store-version-filter ${this.versionFilter}`);
+        return null;
+      }
+
+      const latestVersion = selectVersion(versions, this.config.versionStatePriority);
       const status = latestVersion.attributes.appStoreState as AppStoreReviewStatus;
       const version = latestVersion.attributes.versionString;
 
@@ -53,58 +134,521 @@ export class AppStoreConnectMonitor {
       try {
         const buildRelationship = latestVersion.relationships?.build?.data;
         if (buildRelationship?.id) {
-          const buildResponse = await axios.get(
-            `${this.baseURL}/builds/${buildRelationship.id}`,
-            {
-              headers: {
-                Authorization: `Bearer ${token}`,
-              },
-            }
-          );
+          const buildResponse = await this.request({
+            method: 'get',
+            url: `${this.baseURL}/builds/${buildRelationship.id}`,
+          });
           buildNumber = buildResponse.data.data?.attributes?.version;
         }
       } catch (error) {
-        console.warn('Failed to fetch build number:', error);
+        console.warn(`Failed to fetch build number: ${redact(error instanceof Error ? error.message : error)}`);
+      }
+
+      let phasedRelease: PhasedReleaseInfo | undefined;
+      const phasedRef = latestVersion.relationships?.appStoreVersionPhasedRelease?.data;
+      const phasedAttributes = phasedRef
+        ? (versionsResponse.data.included || []).find(
+            (item: any) => item.type === phasedRef.type && item.id === phasedRef.id
+          )?.attributes
+        : undefined;
+      if (phasedAttributes) {
+        phasedRelease = {
+          state: phasedAttributes.phasedReleaseState,
+          currentDay: phasedAttributes.currentDayNumber ?? undefined,
+        };
+      }
+
+      // The latest review submission containing this version, for the submission date
+      let submittedDate: string | undefined;
+      try {
+        const submissionsResponse = await this.request({
+          method: 'get',
+          url: `${this.baseURL}/reviewSubmissions`,
+          params: {
+            'filter[app]': appId,
+            'filter[platform]': platform,
+            'fields[reviewSubmissions]': 'submittedDate,appStoreVersionForReview',
+            // Relationship linkage is only returned for included relationships
+            'include': 'appStoreVersionForReview',
+            'limit': REVIEW_SUBMISSIONS_PAGE_LIMIT,
+          },
+        });
+        submittedDate = (submissionsResponse.data.data || [])
+          .filter(
+            (submission: any) =>
+              submission.relationships?.appStoreVersionForReview?.data?.id === latestVersion.id &&
+              submission.attributes?.submittedDate
+          )
+          .map((submission: any) => submission.attributes.submittedDate as string)
+          .sort()
+          .pop();
+      } catch (error) {
+        console.warn(`Failed to fetch review submission: ${redact(error instanceof Error ? error.message : error)}`);
       }
 
+      // Reviewer messages live in the Resolution Center, which the App Store Connect API
+      // doesn't expose, so rejectionReason stays unset and notifiers point there instead
       return {
-        appId: this.config.appId,
+        appId: appId,
+        appName: appName || undefined,
+        platform: platform,
         version: version,
         buildNumber: buildNumber,
         status: status,
+        releaseType: latestVersion.attributes.releaseType,
+        phasedRelease: phasedRelease,
+        createdDate: latestVersion.attributes.createdDate || undefined,
+        earliestReleaseDate: latestVersion.attributes.earliestReleaseDate || undefined,
+        submittedDate: submittedDate,
+      };
+    } catch (error) {
+      if (axios.isAxiosError(error)) {
+        console.error('App Store Connect API Error:', error.response?.data || error.message);
+        throw toAppStoreApiError(error);
+      }
+      console.error('Error fetching App Store review status:', error);
+      throw error;
+    }
+  }
+
+  /**
+   * Get the beta review state of the latest uploaded build. TestFlight review is a separate
+   * pipeline from App Store review, tracked through the build's betaAppReviewSubmission.
+   */
+  async getTestFlightStatus(appId: string, platform: string): Promise<TestFlightReviewInfo | null> {
+    try {
+      const buildsResponse = await this.request({
+        method: 'get',
+        url: `${this.baseURL}/builds`,
+        params: {
+          'filter[app]': appId,
+          'filter[preReleaseVersion.platform]': platform,
+          'include': 'preReleaseVersion,betaAppReviewSubmission',
+          'limit': 1,
+          'sort': '-uploadedDate',
+        },
+      });
+
+      const latestBuild = buildsResponse.data.data?.[0];
+      if (!latestBuild) {
+        console.log(`No ${platform} builds found for app ${appId}`);
+        return null;
+      }
+
+      const included: any[] = buildsResponse.data.included || [];
+      const findIncluded = (relationship: any) => {
+        const ref = relationship?.data;
+        return ref ? included.find((item) => item.type === ref.type && item.id === ref.id) : undefined;
+      };
+
+      const submission = findIncluded(latestBuild.relationships?.betaAppReviewSubmission);
+      if (!submission) {
+        console.log(`Latest build ${latestBuild.attributes.version} of app ${appId} has not been submitted for beta review`);
+        return null;
+      }
+
+      const preReleaseVersion = findIncluded(latestBuild.relationships?.preReleaseVersion);
+
+      return {
+        appId: appId,
+        platform: platform,
+        version: preReleaseVersion?.attributes?.version || '',
+        buildNumber: latestBuild.attributes.version,
+        status: submission.attributes.betaReviewState as TestFlightReviewStatus,
+      };
+    } catch (error) {
+      if (axios.isAxiosError(error)) {
+        console.error('App Store Connect API Error:', error.response?.data || error.message);
+        throw toAppStoreApiError(error);
+      }
+      console.error('Error fetching TestFlight review status:', error);
+      throw error;
+    }
+  }
+
+  /**
+   * Get the processing state of the latest uploaded build. Processing failures surface here
+   * long before review, and never show up in appStoreState.
+   */
+  async getBuildProcessingState(appId: string, platform: string): Promise<BuildProcessingInfo | null> {
+    try {
+      const buildsResponse = await this.request({
+        method: 'get',
+        url: `${this.baseURL}/builds`,
+        params: {
+          'filter[app]': appId,
+          'filter[preReleaseVersion.platform]': platform,
+          'include': 'preReleaseVersion',
+          'limit': 1,
+          'sort': '-uploadedDate',
+        },
+      });
+
+      const latestBuild = buildsResponse.data.data?.[0];
+      if (!latestBuild) {
+        console.log(`No ${platform} builds found for app ${appId}`);
+        return null;
+      }
+
+      const versionRef = latestBuild.relationships?.preReleaseVersion?.data;
+      const preReleaseVersion = versionRef
+        ? (buildsResponse.data.included || []).find((item: any) => item.type === versionRef.type && item.id === versionRef.id)
+        : undefined;
+
+      return {
+        appId: appId,
+        platform: platform,
+        version: preReleaseVersion?.attributes?.version || '',
+        buildNumber: latestBuild.attributes.version,
+        processingState: latestBuild.attributes.processingState as BuildProcessingState,
+      };
+    } catch (error) {
+      if (axios.isAxiosError(error)) {
+        console.error('App Store Connect API Error:', error.response?.data || error.message);
+        throw toAppStoreApiError(error);
+      }
+      console.error('Error fetching build processing state:', error);
+      throw error;
+    }
+  }
+
+  /**
+   * Get the state of the app's latest review submission. Under the review submission model a
+   * submission holds the version together with in-app purchases and other items, and its state
+   * covers all of them, which appStoreState doesn't.
+   */
+  async getReviewSubmissionState(appId: string, platform: string): Promise<ReviewSubmissionInfo | null> {
+    try {
+      const submissionsResponse = await this.request({
+        method: 'get',
+        url: `${this.baseURL}/reviewSubmissions`,
+        params: {
+          'filter[app]': appId,
+          'filter[platform]': platform,
+          'filter[state]': SUBMITTED_REVIEW_SUBMISSION_STATES.join(','),
+          'fields[reviewSubmissions]': 'state,submittedDate,appStoreVersionForReview',
+          'fields[appStoreVersions]': 'versionString',
+          'include': 'appStoreVersionForReview',
+          'limit': REVIEW_SUBMISSIONS_PAGE_LIMIT,
+        },
+      });
+
+      const latestSubmission = (submissionsResponse.data.data || [])
+        .filter((submission: any) => submission.attributes?.submittedDate)
+        .sort((a: any, b: any) => Date.parse(a.attributes.submittedDate) - Date.parse(b.attributes.submittedDate))
+        .pop();
+      if (!latestSubmission) {
+        console.log(`No ${platform} review submissions found for app ${appId}`);
+        return null;
+      }
+
+      const versionRef = latestSubmission.relationships?.appStoreVersionForReview?.data;
+      const appStoreVersion = versionRef
+        ? (submissionsResponse.data.included || []).find((item: any) => item.type === versionRef.type && item.id === versionRef.id)
+        : undefined;
+
+      return {
+        appId: appId,
+        platform: platform,
+        submissionId: latestSubmission.id,
+        version: appStoreVersion?.attributes?.versionString || undefined,
+        state: latestSubmission.attributes.state as ReviewSubmissionState,
+        submittedDate: latestSubmission.attributes.submittedDate,
       };
     } catch (error) {
       if (axios.isAxiosError(error)) {
         console.error('App Store Connect API Error:', error.response?.data || error.message);
-      } else {
-        console.error('Error fetching App Store review status:', error);
+        throw toAppStoreApiError(error);
+      }
+      console.error('Error fetching review submission state:', error);
+      throw error;
+    }
+  }
+
+  /**
+   * Get the review state of the app's in-app purchases and auto-renewable subscriptions
+   */
+  async getInAppPurchaseStates(appId: string): Promise<InAppPurchaseInfo[]> {
+    try {
+      const purchasesResponse = await this.request({
+        method: 'get',
+        url: `${this.baseURL}/apps/${appId}/inAppPurchasesV2`,
+        params: {
+          'fields[inAppPurchases]': 'name,productId,state',
+          'limit': IN_APP_PURCHASES_PAGE_LIMIT,
+        },
+      });
+
+      // Subscriptions are only reachable through their subscription groups
+      const groupsResponse = await this.request({
+        method: 'get',
+        url: `${this.baseURL}/apps/${appId}/subscriptionGroups`,
+        params: {
+          'include': 'subscriptions',
+          'fields[subscriptions]': 'name,productId,state',
+          'limit': IN_APP_PURCHASES_PAGE_LIMIT,
+          'limit[subscriptions]': INCLUDED_SUBSCRIPTIONS_LIMIT,
+        },
+      });
+
+      const toInfo = (item: any, kind: InAppPurchaseKind): InAppPurchaseInfo => ({
+        appId: appId,
+        productId: item.attributes?.productId || item.id,
+        name: item.attributes?.name || item.attributes?.productId || item.id,
+        kind: kind,
+        state: item.attributes?.state,
+      });
+
+      return [
+        ...(purchasesResponse.data.data || []).map((item: any) => toInfo(item, 'In-App Purchase')),
+        ...(groupsResponse.data.included || [])
+          .filter((item: any) => item.type === 'subscriptions')
+          .map((item: any) => toInfo(item, 'Subscription')),
+      ].filter((info) => !!info.state);
+    } catch (error) {
+      if (axios.isAxiosError(error)) {
+        console.error('App Store Connect API Error:', error.response?.data || error.message);
+        throw toAppStoreApiError(error);
+      }
+      console.error('Error fetching in-app purchase states:', error);
+      throw error;
+    }
+  }
+
+  /**
+   * Sign a token and fetch the app's name, confirming the API key can read the app.
+   * Returns the app name.
+   */
+  async checkCredentials(appId: string): Promise<string> {
+    return this.getAppName(appId);
+  }
+
+  /**
+   * ID of the key requests are signed with. After a successful request, that is the key
+   * that authenticated, which may not be the first of several.
+   */
+  getKeyId(): string {
+    return this.config.keys[this.keyIndex].keyId;
+  }
+
+  private async getAppName(appId: string): Promise<string> {
+    const response = await this.request({
+      method: 'get',
+      url: `${this.baseURL}/apps/${appId}`,
+      params: {
+        'fields[apps]': 'name',
+      },
+    });
+    return response.data.data?.attributes?.name;
+  }
+
+  /**
+   * Numeric app IDs to monitor: app-store-app-id followed by the apps resolved from
+   * app-store-bundle-id, without duplicates. A bundle ID that can't be resolved is passed to
+   * onError and left out, so the other apps are still checked.
+   */
+  async resolveAppIds(onError: (bundleId: string, error: unknown) => void): Promise<string[]> {
+    const appIds = [...this.config.appIds];
+    for (const bundleId of this.config.bundleIds) {
+      let appId: string;
+      try {
+        appId = await this.findAppIdByBundleId(bundleId);
+      } catch (error) {
+        onError(bundleId, axios.isAxiosError(error) ? toAppStoreApiError(error) : error);
+        continue;
+      }
+      core.info(`Resolved bundle ID ${bundleId} to App Store app ID ${appId}`);
+      if (!appIds.includes(appId)) {
+        appIds.push(appId);
+      }
+    }
+    return appIds;
+  }
+
+  /**
+   * List every app visible to the API key, following the pagination links
+   */
+  async listApps(): Promise<AppStoreAppInfo[]> {
+    const apps: AppStoreAppInfo[] = [];
+    let url: string | undefined = `${this.baseURL}/apps`;
+    let params: Record<string, string | number> | undefined = {
+      'fields[apps]': 'name,bundleId',
+      limit: APPS_PAGE_LIMIT,
+    };
+
+    while (url) {
+      const response: AxiosResponse = await this.request({ method: 'get', url, params });
+      for (const app of response.data.data || []) {
+        apps.push({ appId: app.id, name: app.attributes?.name, bundleId: app.attributes?.bundleId });
+      }
+      // The next link already carries the query parameters
+      url = response.data.links?.next;
+      params = undefined;
+    }
+    return apps;
+  }
+
+  /**
+   * Look up the numeric app ID (Apple ID) of an app by its bundle ID
+   */
+  async findAppIdByBundleId(bundleId: string): Promise<string> {
+    const response = await this.request({
+      method: 'get',
+      url: `${this.baseURL}/apps`,
+      params: {
+        'filter[bundleId]': bundleId,
+        'fields[apps]': 'bundleId',
+      },
+    });
+
+    // The filter isn't guaranteed to be an exact match, so compare the bundle IDs again
+    const app = (response.data.data || []).find((a: any) => a.attributes?.bundleId === bundleId);
+    if (!app) {
+      throw new AppStoreApiError(
+        `No App Store Connect app with bundle ID ${bundleId} is visible to this API key. Check the bundle ID and the key's access to the app`,
+        'not_found'
+      );
+    }
+    return app.id;
+  }
+
+  /**
+   * Send an authenticated request. A 401 is retried once with a new token, then with each of
+   * the remaining keys in turn, and the key that worked is kept for the rest of the run.
+   */
+  private async request<T = any>(config: AxiosRequestConfig): Promise<AxiosResponse<T>> {
+    let retriedToken = false;
+    for (;;) {
+      let token: string;
+      try {
+        token = this.getToken();
+      } catch (error) {
+        // A malformed key fails before any request is made
+        this.useNextKey(error, `${error}`);
+        continue;
+      }
+
+      try {
+        const response = await this.http.request<T>(this.withAuthorization(config, token));
+        if (!this.keyAuthenticated) {
+          this.keyAuthenticated = true;
+          if (this.config.keys.length > 1) {
+            core.info(
+              `Authenticated with App Store Connect key ${this.keyIndex + 1} of ${this.config.keys.length} (${this.config.keys[this.keyIndex].keyId})`
+            );
+          }
+        }
+        return response;
+      } catch (error) {
+        if (!axios.isAxiosError(error) || error.response?.status !== 401) {
+          throw error;
+        }
+
+        if (!retriedToken) {
+          core.warning(
+            `App Store Connect rejected the token for ${config.url} (HTTP 401), retrying once with a new token. If this persists, check that the runner's clock is in sync`
+          );
+          retriedToken = true;
+          this.token = undefined;
+          continue;
+        }
+        this.useNextKey(error, 'HTTP 401');
       }
+    }
+  }
+
+  /**
+   * Switch to the next configured key, or rethrow when there is none or a key already worked
+   */
+  private useNextKey(error: unknown, reason: string): void {
+    if (this.keyAuthenticated || this.keyIndex + 1 >= this.config.keys.length) {
       throw error;
     }
+
+    core.warning(
+      `App Store Connect key ${this.keyIndex + 1} of ${this.config.keys.length} (${this.config.keys[this.keyIndex].keyId}) failed (${reason}), trying the next key`
+    );
+    this.keyIndex++;
+    this.token = undefined;
+  }
+
+  private withAuthorization(config: AxiosRequestConfig, token: string): AxiosRequestConfig {
+    return {
+      ...config,
+      headers: {
+        ...config.headers,
+        Authorization: `Bearer ${token}`,
+      },
+    };
   }
 
-  private generateToken(): string {
+  /**
+   * Reuse the signed token until shortly before it expires
+   */
+  private getToken(): string {
     const now = Math.floor(Date.now() / 1000);
-    const exp = now + 20 * 60; // 20 minutes
+    if (!this.token || this.token.expiresAt - TOKEN_SAFETY_MARGIN_SECONDS <= now) {
+      this.token = this.generateToken(now);
+    }
+    return this.token.value;
+  }
+
+  private generateToken(now: number): { value: string; expiresAt: number } {
+    const iat = now - TOKEN_BACKDATE_SECONDS;
+    const exp = iat + TOKEN_MAX_LIFETIME_SECONDS - TOKEN_SAFETY_MARGIN_SECONDS;
 
     const payload = {
       iss: this.config.issuerId,
-      iat: now,
+      iat: iat,
       exp: exp,
       aud: 'appstoreconnect-v1',
     };
 
-    // Decode base64 private key if needed
-    let privateKey = this.config.privateKey;
-    if (!privateKey.includes('BEGIN PRIVATE KEY')) {
-      privateKey = Buffer.from(privateKey, 'base64').toString('utf-8');
-    }
+    const key = this.config.keys[this.keyIndex];
+
+    // Read from file, decode base64 and accept PKCS#8 or SEC1 keys
+    const privateKey = parseAppStorePrivateKey(key.privateKey);
 
     const token = jwt.sign(payload, privateKey, {
       algorithm: 'ES256',
-      keyid: this.config.keyId,
+      keyid: key.keyId,
     });
+    // Failed requests quote their headers, so the token is scrubbed like the configured secrets
+    registerSecret(token);
 
-    return token;
+    return { value: token, expiresAt: exp };
   }
 }
+
+/**
+ * Describe an App Store Connect error response (after HttpClient has exhausted its retries
+ * and every configured key got a 401)
+ */
+export function toAppStoreApiError(error: AxiosError): AppStoreApiError {
+  const status = error.response?.status;
+  const data: any = error.response?.data;
+  const detail = data?.errors?.[0]?.detail || data?.errors?.[0]?.title || error.message;
+
+  if (status === 401) {
+    return new AppStoreApiError(
+      `App Store Connect API authentication failed (HTTP 401): ${detail}. Check app-store-issuer-id, app-store-key-id and app-store-private-key`,
+      'auth',
+      status
+    );
+  }
+
+  // The key is valid but its role can't read this resource, which isn't an invalid credential
+  if (status === 403) {
+    return new AppStoreApiError(
+      `App Store Connect API request was forbidden (HTTP 403): ${detail}. Check the API key's role and app access`,
+      'other',
+      status
+    );
+  }
+
+  return new AppStoreApiError(
+    `App Store Connect API request failed (HTTP ${status ?? 'no response'}): ${detail}`,
+    getHttpErrorKind(status),
+    status
+  );
+}
@@ -1,5 +1,10 @@
-import axios from 'axios';
-import { GooglePlayConfig, GooglePlayReviewInfo, GooglePlayReviewStatus } from '../types';
+import * as core from '@actions/core';
+import axios, { AxiosError, AxiosResponse } from 'axios';
+import { GooglePlayConfig, GooglePlayReviewInfo, GooglePlayReviewStatus, GooglePlayTrackInfo } from '../types';
+import { ApiErrorKind, getHttpErrorKind, StoreApiError } from '../utils/apiError';
+import { parseGooglePrivateKey, parseGoogleServiceAccount } from '../utils/credentials';
+import { HttpRequester, parseRetryAfter, sleep } from '../utils/http';
+import { registerSecret } from '../utils/redact';
 
 interface GooglePlayServiceAccount {
   type: string;
@@ -14,90 +19,260 @@ interface GooglePlayServiceAccount {
   client_x509_cert_url: string;
 }
 
+const DEFAULT_API_BASE_URL = 'https://androidpublisher.googleapis.com';
+const DEFAULT_TOKEN_URL = 'https://oauth2.googleapis.com/token';
+
+// Error reasons Google APIs use for quota exhaustion, which can arrive as 403 instead of 429
+const QUOTA_ERROR_REASONS = ['rateLimitExceeded', 'userRateLimitExceeded', 'quotaExceeded', 'dailyLimitExceeded'];
+
+// 409 isn't retried by HttpClient, since it usually means another edit is open
+const EDIT_CONFLICT_RETRIES = 1;
+const EDIT_CONFLICT_RETRY_DELAY_MS = 5000;
+
+/**
+ * Google Play API failure, classified so operators can tell whether to raise their
+ * API quota or fix the service account
+ */
+export class GooglePlayApiError extends StoreApiError {
+  readonly retryAfterMs?: number;
+
+  constructor(message: string, kind: ApiErrorKind, status?: number, retryAfterMs?: number) {
+    super(message, kind, status);
+    this.name = 'GooglePlayApiError';
+    this.retryAfterMs = retryAfterMs;
+  }
+}
+
 export class GooglePlayConsoleMonitor {
   private config: GooglePlayConfig;
   private serviceAccount: GooglePlayServiceAccount;
-  private baseURL = 'https://androidpublisher.googleapis.com/androidpublisher/v3';
+  private http: HttpRequester;
+  private baseURL: string;
+  private tokenURL: string;
 
-  constructor(config: GooglePlayConfig) {
+  constructor(config: GooglePlayConfig, http: HttpRequester) {
     this.config = config;
+    this.http = http;
+    this.baseURL = `${config.apiBaseUrl || DEFAULT_API_BASE_URL}/androidpublisher/v3`;
+    this.tokenURL = config.tokenUrl || DEFAULT_TOKEN_URL;
 
-    // Parse service account JSON
-    let serviceAccountJson = config.serviceAccount;
-    if (!serviceAccountJson.includes('{')) {
-      // Decode base64 if needed
-      serviceAccountJson = Buffer.from(serviceAccountJson, 'base64').toString('utf-8');
-    }
-    this.serviceAccount = JSON.parse(serviceAccountJson);
+    // Parse service account JSON, reading it from a file if a path was given
+    this.serviceAccount = parseGoogleServiceAccount(config.serviceAccount);
   }
 
-  async getReviewStatus(): Promise<GooglePlayReviewInfo | null> {
+  /**
+   * Get the latest release of each configured track. The app title is read from the same
+   * edit, and only when no cachedAppName is given.
+   */
+  async getReviewStatus(cachedAppName?: string): Promise<GooglePlayReviewInfo[]> {
     try {
       const accessToken = await this.getAccessToken();
 
-      // Get edits (drafts) for the app
-      const editsResponse = await axios.post(
-        `${this.baseURL}/applications/${this.config.packageName}/edits`,
-        {},
-        {
+      const editId = await this.createEdit(accessToken);
+
+      // Get tracks to find the latest version in review, deleting the edit even if this fails
+      // so abandoned edits don't pile up in the Play Console
+      let tracksResponse: AxiosResponse;
+      let appName = cachedAppName;
+      try {
+        tracksResponse = await this.http.request({
+          method: 'get',
+          url: `${this.baseURL}/applications/${this.config.packageName}/edits/${editId}/tracks`,
           headers: {
             Authorization: `Bearer ${accessToken}`,
-            'Content-Type': 'application/json',
           },
+        });
+
+        if (appName === undefined) {
+          appName = await this.getAppTitle(editId, accessToken);
         }
-      );
+      } finally {
+        await this.deleteEdit(editId, accessToken);
+      }
 
-      const editId = editsResponse.data.id;
+      const results: GooglePlayReviewInfo[] = [];
+      for (const trackName of this.config.tracks) {
+        const track = tracksResponse.data.tracks?.find(
+          (t: any) => t.track === trackName
+        );
 
-      // Get tracks to find the latest version in review
-      const tracksResponse = await axios.get(
-        `${this.baseURL}/applications/${this.config.packageName}/edits/${editId}/tracks`,
-        {
-          headers: {
-            Authorization: `Bearer ${accessToken}`,
-          },
+        if (!track || !track.releases || track.releases.length === 0) {
+          console.log(`No ${trackName} releases found`);
+          continue;
         }
-      );
 
-      // Find production track
-      const productionTrack = tracksResponse.data.tracks?.find(
-        (track: any) => track.track === 'production'
-      );
+        const latestRelease = track.releases[0];
+        results.push({
+          packageName: this.config.packageName,
+          appName: appName,
+          track: trackName,
+          versionCode: latestRelease.versionCodes?.[0],
+          versionName: latestRelease.name,
+          status: this.mapStatus(latestRelease.status),
+          userFraction: latestRelease.userFraction,
+        });
+      }
 
-      if (!productionTrack || !productionTrack.releases || productionTrack.releases.length === 0) {
-        console.log('No production releases found');
-        return null;
+      return results;
+    } catch (error) {
+      if (axios.isAxiosError(error)) {
+        console.error('Google Play Console API Error:', error.response?.data || error.message);
+        throw toGooglePlayApiError(error);
       }
+      console.error('Error fetching Google Play review status:', error);
+      throw error;
+    }
+  }
 
-      const latestRelease = productionTrack.releases[0];
-      const versionCode = latestRelease.versionCodes?.[0];
-      const status = this.mapStatus(latestRelease.status);
+  /**
+   * List every track of the package, including closed testing tracks with custom names,
+   * with its latest release
+   */
+  async listTracks(): Promise<GooglePlayTrackInfo[]> {
+    try {
+      const accessToken = await this.getAccessToken();
+      const editId = await this.createEdit(accessToken);
 
-      // Clean up the edit
-      await axios.delete(
-        `${this.baseURL}/applications/${this.config.packageName}/edits/${editId}`,
-        {
+      let tracksResponse: AxiosResponse;
+      try {
+        tracksResponse = await this.http.request({
+          method: 'get',
+          url: `${this.baseURL}/applications/${this.config.packageName}/edits/${editId}/tracks`,
           headers: {
             Authorization: `Bearer ${accessToken}`,
           },
-        }
-      );
+        });
+      } finally {
+        await this.deleteEdit(editId, accessToken);
+      }
 
-      return {
-        packageName: this.config.packageName,
-        versionCode: versionCode,
-        status: status,
-      };
+      return (tracksResponse.data.tracks || []).map((track: any) => {
+        const releases: any[] = track.releases || [];
+        return {
+          track: track.track,
+          releaseCount: releases.length,
+          latestRelease: releases[0] ? { name: releases[0].name, status: releases[0].status } : undefined,
+        };
+      });
     } catch (error) {
       if (axios.isAxiosError(error)) {
         console.error('Google Play Console API Error:', error.response?.data || error.message);
-      } else {
-        console.error('Error fetching Google Play review status:', error);
+        throw toGooglePlayApiError(error);
       }
       throw error;
     }
   }
 
+  /**
+   * Open an edit (draft) for reading the tracks, retrying once if it conflicts with another edit.
+   * The API can't list open edits or create read-only ones, so a conflicting edit left by another
+   * run can't be reused or deleted here; it is given a moment to be committed, deleted or expire.
+   * This is the first call on the package, so a refusal here means the service account isn't
+   * linked to it in the Play Console.
+   */
+  private async createEdit(accessToken: string): Promise<string> {
+    for (let attempt = 0; ; attempt++) {
+      try {
+        const response = await this.http.request({
+          method: 'post',
+          url: `${this.baseURL}/applications/${this.config.packageName}/edits`,
+          data: {},
+          headers: {
+            Authorization: `Bearer ${accessToken}`,
+            'Content-Type': 'application/json',
+          },
+        });
+        return response.data.id;
+      } catch (error) {
+        if (axios.isAxiosError(error) && (error.response?.status === 401 || error.response?.status === 403)) {
+          throw this.toPackageAccessError(error);
+        }
+        if (attempt >= EDIT_CONFLICT_RETRIES || !axios.isAxiosError(error) || error.response?.status !== 409) {
+          throw error;
+        }
+        core.warning(
+          `Google Play edit creation conflicted with another open edit, retrying in ${EDIT_CONFLICT_RETRY_DELAY_MS / 1000}s`
+        );
+        await sleep(EDIT_CONFLICT_RETRY_DELAY_MS);
+      }
+    }
+  }
+
+  /**
+   * Name the service account and package in a permission error, since the API's own message
+   * doesn't say which account was refused. Quota errors arriving as 403 are left as they are.
+   */
+  private toPackageAccessError(error: AxiosError): GooglePlayApiError {
+    const apiError = toGooglePlayApiError(error);
+    if (apiError.kind !== 'auth') {
+      return apiError;
+    }
+
+    const data: any = error.response?.data;
+    return new GooglePlayApiError(
+      `Service account ${this.serviceAccount.client_email} lacks access to package ${this.config.packageName}; grant it in Play Console > Users and permissions (HTTP ${apiError.status}: ${data?.error?.message || error.message})`,
+      'auth',
+      apiError.status
+    );
+  }
+
+  /**
+   * Read the store listing title in the app's default language. Failures only skip the title,
+   * since the service account may lack access to store listings.
+   */
+  private async getAppTitle(editId: string, accessToken: string): Promise<string | undefined> {
+    const editUrl = `${this.baseURL}/applications/${this.config.packageName}/edits/${editId}`;
+    const headers = { Authorization: `Bearer ${accessToken}` };
+
+    try {
+      const detailsResponse = await this.http.request({ method: 'get', url: `${editUrl}/details`, headers });
+      const language = detailsResponse.data.defaultLanguage;
+      if (!language) {
+        return undefined;
+      }
+
+      const listingResponse = await this.http.request({
+        method: 'get',
+        url: `${editUrl}/listings/${encodeURIComponent(language)}`,
+        headers,
+      });
+      return listingResponse.data.title || undefined;
+    } catch (error) {
+      core.warning(
+        `Failed to read the Google Play listing title of ${this.config.packageName}: ${axios.isAxiosError(error) ? error.message : error}`
+      );
+      return undefined;
+    }
+  }
+
+  private async deleteEdit(editId: string, accessToken: string): Promise<void> {
+    try {
+      await this.http.request({
+        method: 'delete',
+        url: `${this.baseURL}/applications/${this.config.packageName}/edits/${editId}`,
+        headers: {
+          Authorization: `Bearer ${accessToken}`,
+        },
+      });
+    } catch (error) {
+      // Don't mask the tracks result (or its error); the edit expires on its own eventually
+      core.warning(`Failed to delete Google Play edit ${editId}: ${axios.isAxiosError(error) ? error.message : error}`);
+    }
+  }
+
+  /**
+   * Exchange the service account key for an access token without opening an edit.
+   * Returns the service account email.
+   */
+  async checkCredentials(): Promise<string> {
+    try {
+      await this.getAccessToken();
+    } catch (error) {
+      throw axios.isAxiosError(error) ? toGooglePlayApiError(error) : error;
+    }
+    return this.serviceAccount.client_email;
+  }
+
   private async getAccessToken(): Promise<string> {
     const now = Math.floor(Date.now() / 1000);
     const exp = now + 3600; // 1 hour
@@ -110,32 +285,34 @@ export class GooglePlayConsoleMonitor {
     const jwtClaim = {
       iss: this.serviceAccount.client_email,
       scope: 'https://www.googleapis.com/auth/androidpublisher',
-      aud: 'https://oauth2.googleapis.com/token',
+      aud: this.tokenURL,
       iat: now,
       exp: exp,
     };
 
     // Use jsonwebtoken to sign the JWT
     const jwt = require('jsonwebtoken');
-    const assertion = jwt.sign(jwtClaim, this.serviceAccount.private_key, {
+    const assertion = jwt.sign(jwtClaim, parseGooglePrivateKey(this.serviceAccount.private_key), {
       algorithm: 'RS256',
       header: jwtHeader,
     });
+    // The assertion and the access token it buys both end up in request errors
+    registerSecret(assertion);
 
     // Exchange JWT for access token
-    const response = await axios.post(
-      'https://oauth2.googleapis.com/token',
-      new URLSearchParams({
+    const response = await this.http.request({
+      method: 'post',
+      url: this.tokenURL,
+      data: new URLSearchParams({
         grant_type: 'urn:ietf:params:oauth:grant-type:jwt-bearer',
         assertion: assertion,
       }).toString(),
-      {
-        headers: {
-          'Content-Type': 'application/x-www-form-urlencoded',
-        },
-      }
-    );
+      headers: {
+        'Content-Type': 'application/x-www-form-urlencoded',
+      },
+    });
 
+    registerSecret(response.data.access_token);
     return response.data.access_token;
   }
 
@@ -154,3 +331,39 @@ export class GooglePlayConsoleMonitor {
     }
   }
 }
+
+/**
+ * Classify an Android Publisher or OAuth error response (after HttpClient has exhausted its retries)
+ */
+export function toGooglePlayApiError(error: AxiosError): GooglePlayApiError {
+  const status = error.response?.status;
+  const data: any = error.response?.data;
+  const detail = data?.error?.message || data?.error_description || data?.error || error.message;
+  const reasons: string[] = (data?.error?.errors || []).map((e: any) => e.reason);
+
+  if (status === 429 || data?.error?.status === 'RESOURCE_EXHAUSTED' || reasons.some((r) => QUOTA_ERROR_REASONS.includes(r))) {
+    const retryAfterMs = parseRetryAfter(error.response?.headers?.['retry-after']);
+    const retryHint = retryAfterMs !== undefined ? `, retry after ${Math.ceil(retryAfterMs / 1000)}s` : '';
+    return new GooglePlayApiError(
+      `Google Play API quota exhausted (HTTP ${status}${retryHint}): ${detail}. Request a higher Android Publisher API quota in the Google Cloud Console or run less often`,
+      'rate_limited',
+      status,
+      retryAfterMs
+    );
+  }
+
+  // The token endpoint answers 400 invalid_grant for revoked or malformed service account keys
+  if (status === 401 || status === 403 || data?.error === 'invalid_grant') {
+    return new GooglePlayApiError(
+      `Google Play API authentication failed (HTTP ${status}): ${detail}. Check the service account key and its Play Console permissions`,
+      'auth',
+      status
+    );
+  }
+
+  return new GooglePlayApiError(
+    `Google Play API request failed (HTTP ${status ?? 'no response'}): ${detail}`,
+    getHttpErrorKind(status),
+    status
+  );
+}
@@ -0,0 +1,160 @@
+import axios, { AxiosError } from 'axios';
+import { HuaweiConfig, HuaweiReviewInfo, HuaweiReviewStatus } from '../types';
+import { ApiErrorKind, getHttpErrorKind, StoreApiError } from '../utils/apiError';
+import { HttpRequester } from '../utils/http';
+import { registerSecret } from '../utils/redact';
+
+// AppInfo.releaseState codes of the Publishing API
+const RELEASE_STATES: Record<number, HuaweiReviewStatus> = {
+  0: HuaweiReviewStatus.RELEASED,
+  1: HuaweiReviewStatus.RELEASE_REJECTED,
+  2: HuaweiReviewStatus.REMOVED_FROM_SALE,
+  3: HuaweiReviewStatus.RELEASING,
+  4: HuaweiReviewStatus.IN_REVIEW,
+  5: HuaweiReviewStatus.UPDATING,
+  6: HuaweiReviewStatus.REMOVAL_REQUESTED,
+  7: HuaweiReviewStatus.DRAFT,
+  8: HuaweiReviewStatus.UPDATE_REJECTED,
+  11: HuaweiReviewStatus.RELEASE_CANCELED,
+};
+
+/**
+ * AppGallery Connect API failure. The API reports most errors in the ret field of an HTTP 200
+ * response, so code is the ret code when there is one and the HTTP status otherwise.
+ */
+export class HuaweiApiError extends StoreApiError {
+  readonly code?: number;
+
+  constructor(message: string, kind: ApiErrorKind, code?: number) {
+    super(message, kind);
+    this.name = 'HuaweiApiError';
+    this.code = code;
+  }
+}
+
+export class HuaweiAppGalleryMonitor {
+  private config: HuaweiConfig;
+  private http: HttpRequester;
+  private baseURL = 'https://connect-api.cloud.huawei.com/api';
+
+  constructor(config: HuaweiConfig, http: HttpRequester) {
+    this.config = config;
+    this.http = http;
+  }
+
+  /**
+   * Get the release state of the app's latest version, with the app name in its default
+   * language and the reviewer's opinion when it was rejected
+   */
+  async getHuaweiReviewStatus(): Promise<HuaweiReviewInfo | undefined> {
+    try {
+      const accessToken = await this.getAccessToken();
+
+      const response = await this.http.request({
+        method: 'get',
+        url: `${this.baseURL}/publish/v2/app-info`,
+        params: { appId: this.config.appId },
+        headers: {
+          client_id: this.config.clientId,
+          Authorization: `Bearer ${accessToken}`,
+        },
+      });
+      checkRet(response.data, 'Reading the app info');
+
+      const appInfo = response.data.appInfo;
+      const version = appInfo?.versionNumber || appInfo?.onShelfVersionNumber;
+      if (!appInfo || !version) {
+        console.log('No Huawei AppGallery version found');
+        return undefined;
+      }
+
+      const languages: any[] = response.data.languages || [];
+      const language = languages.find((l) => l.lang === appInfo.defaultLang) || languages[0];
+      // Unmapped codes are reported as-is rather than guessed
+      const status = RELEASE_STATES[appInfo.releaseState] ?? `RELEASE_STATE_${appInfo.releaseState}`;
+
+      return {
+        appId: this.config.appId,
+        appName: language?.appName || undefined,
+        version: `${version}`,
+        status: status,
+        rejectionReason: response.data.auditInfo?.auditOpinion || undefined,
+      };
+    } catch (error) {
+      if (axios.isAxiosError(error)) {
+        console.error('Huawei AppGallery Connect API Error:', error.response?.data || error.message);
+        throw toHuaweiApiError(error);
+      }
+      console.error('Error fetching Huawei AppGallery review status:', error);
+      throw error;
+    }
+  }
+
+  /**
+   * Exchange the API client credentials for an access token without reading the app.
+   * Returns the client ID.
+   */
+  async checkCredentials(): Promise<string> {
+    try {
+      await this.getAccessToken();
+    } catch (error) {
+      throw axios.isAxiosError(error) ? toHuaweiApiError(error) : error;
+    }
+    return this.config.clientId;
+  }
+
+  private async getAccessToken(): Promise<string> {
+    const response = await this.http.request({
+      method: 'post',
+      url: `${this.baseURL}/oauth2/v1/token`,
+      data: {
+        grant_type: 'client_credentials',
+        client_id: this.config.clientId,
+        client_secret: this.config.clientSecret,
+      },
+      headers: {
+        'Content-Type': 'application/json',
+      },
+    });
+
+    if (!response.data?.access_token) {
+      checkRet(response.data, 'Authentication');
+      throw new HuaweiApiError('AppGallery Connect API authentication failed: no access token returned', 'auth');
+    }
+    registerSecret(response.data.access_token);
+    return response.data.access_token;
+  }
+}
+
+/**
+ * Throw for a non-zero ret code, which the API returns with HTTP 200
+ */
+function checkRet(data: any, action: string): void {
+  const code = data?.ret?.code;
+  if (code !== undefined && code !== 0) {
+    throw new HuaweiApiError(`${action} failed with AppGallery Connect API error ${code}: ${data.ret.msg}`, 'other', code);
+  }
+}
+
+/**
+ * Describe an AppGallery Connect error response (after HttpClient has exhausted its retries)
+ */
+export function toHuaweiApiError(error: AxiosError): HuaweiApiError {
+  const status = error.response?.status;
+  const data: any = error.response?.data;
+  const detail = data?.ret?.msg || data?.error_description || data?.error || error.message;
+
+  if (status === 401 || status === 403) {
+    return new HuaweiApiError(
+      `AppGallery Connect API authentication failed (HTTP ${status}): ${detail}. Check the API client ID and secret`,
+      'auth',
+      status
+    );
+  }
+
+  return new HuaweiApiError(
+    `AppGallery Connect API request failed (HTTP ${status ?? 'no response'}): ${detail}`,
+    getHttpErrorKind(status),
+    status
+  );
+}
@@ -0,0 +1,142 @@
+import * as core from '@actions/core';
+import * as nodemailer from 'nodemailer';
+import { EmailConfig, NotificationPayload, Notifier } from '../types';
+import { getMessages, Language } from '../types/i18n';
+import { redact } from '../utils/redact';
+import { formatCheckedAt, formatReleaseInfo, formatStatus, formatTimestamp, formatTitle, getStatusColor, getStatusEmoji, getStatusHexColor } from '../utils/status';
+
+// Implicit TLS; other ports upgrade with STARTTLS when the server offers it
+const SMTPS_PORT = 465;
+
+export class EmailNotifier implements Notifier {
+  private config: EmailConfig;
+  private transporter: nodemailer.Transporter;
+  private language: Language;
+
+  constructor(config: EmailConfig) {
+    this.config = config;
+    this.language = config.language || 'en';
+
+    if (!config.host || config.to.length === 0) {
+      throw new Error('host and at least one recipient must be provided for email notifications');
+    }
+
+    this.transporter = nodemailer.createTransport({
+      host: config.host,
+      port: config.port,
+      secure: config.port === SMTPS_PORT,
+      auth: {
+        user: config.username,
+        pass: config.password,
+      },
+    });
+  }
+
+  async validate(): Promise<void> {
+    await this.transporter.verify();
+    core.info(`SMTP server ${this.config.host}:${this.config.port} accepted the credentials`);
+  }
+
+  async sendNotification(payload: NotificationPayload): Promise<void> {
+    const messages = getMessages(this.language);
+    const emoji = getStatusEmoji(payload.currentStatus);
+    const color = getStatusHexColor(payload.currentStatus);
+    const checkedAt = formatCheckedAt(new Date());
+    const releaseInfo = formatReleaseInfo(payload, messages);
+
+    const regressionPrefix = payload.regression ? `⚠️ ${messages.regression}: ` : '';
+    const appPrefix = payload.appName ? `${payload.appName}: ` : '';
+    const subject = `${regressionPrefix}${emoji} ${appPrefix}${messages.fallbackMessage(payload.platform, formatStatus(payload.currentStatus))}`;
+
+    const rows: [string, string][] = [
+      [messages.platform, payload.platform],
+      [messages.version, payload.version],
+      [messages.currentStatus, formatStatus(payload.currentStatus)],
+      ...(payload.previousStatus
+        ? [[messages.previousStatus, formatStatus(payload.previousStatus)] as [string, string]]
+        : []),
+      ...(payload.rolloutPercentage !== undefined
+        ? [[messages.rollout, `${payload.rolloutPercentage}%`] as [string, string]]
+        : []),
+      ...(getStatusColor(payload.currentStatus) === 'danger'
+        ? [
+            [
+              messages.rejectionReason,
+              payload.rejectionReason || messages.rejectionReasonUnavailable(payload.platform),
+            ] as [string, string],
+          ]
+        : []),
+      ...(releaseInfo ? [[messages.release, releaseInfo] as [string, string]] : []),
+      ...(payload.submittedAt
+        ? [[messages.submitted, formatTimestamp(payload.submittedAt)] as [string, string]]
+        : []),
+      ...(payload.daysInReview !== undefined
+        ? [[messages.daysInReview, `${payload.daysInReview}`] as [string, string]]
+        : []),
+      ...(payload.appName ? [[messages.appName, payload.appName] as [string, string]] : []),
+      [messages.checkedAt, checkedAt],
+    ];
+
+    const html = `<div style="font-family: sans-serif; border-left: 6px solid ${color}; padding: 8px 16px;">
+  <h2 style="margin: 0 0 12px; color: ${color};">${escapeHtml(`${regressionPrefix}${emoji} ${formatTitle(payload, messages)}`)}</h2>
+  ${payload.actionRequired ? `<p><strong>🚀 ${escapeHtml(messages.actionRequiredRelease)}</strong></p>` : ''}
+  ${payload.rolloutCompleted ? `<p><strong>🎉 ${escapeHtml(messages.rolloutCompleted)}</strong></p>` : ''}
+  ${payload.nowLive ? `<p><strong>🎉 ${escapeHtml(messages.nowLive)}</strong>${payload.storeUrl ? ` <a href="${escapeHtml(payload.storeUrl)}">${escapeHtml(messages.viewListing)}</a>` : ''}</p>` : ''}
+  ${payload.credentialsInvalid ? `<p><strong>🔑 ${escapeHtml(messages.credentialsInvalid(payload.platform))}</strong></p>` : ''}
+  ${payload.reminderHours !== undefined ? `<p>⏰ ${escapeHtml(messages.statusReminder(payload.reminderHours))}</p>` : ''}
+  ${payload.monitoringLapsedHours !== undefined ? `<p>⚠️ ${escapeHtml(messages.monitoringLapsed(payload.monitoringLapsedHours))}</p>` : ''}
+  ${payload.flapping ? `<p>🔁 ${escapeHtml(messages.flappingDetected(payload.flapping.maxTransitions, payload.flapping.windowHours))}</p>` : ''}
+  <table style="border-collapse: collapse;">
+${rows
+  .map(
+    ([label, value]) =>
+      `    <tr><th style="text-align: left; padding: 4px 16px 4px 0;">${escapeHtml(label)}</th><td style="padding: 4px 0;">${escapeHtml(value)}</td></tr>`
+  )
+  .join('\n')}
+  </table>
+</div>`;
+
+    const text = rows.map(([label, value]) => `${label}: ${value}`).join('\n');
+
+    if (this.config.dryRun) {
+      core.info(redact(`[dry-run] Email to ${this.config.to.join(', ')}: ${subject}\n${text}`));
+      return;
+    }
+
+    await this.transporter.sendMail({
+      from: this.config.from,
+      to: this.config.to,
+      subject: subject,
+      text: text,
+      html: html,
+    });
+  }
+
+  async sendHeartbeat(checkedCount: number): Promise<void> {
+    const messages = getMessages(this.language);
+    const subject = `💚 ${messages.heartbeat(checkedCount)}`;
+    const text = `${messages.checkedAt}: ${formatCheckedAt(new Date())}`;
+
+    if (this.config.dryRun) {
+      core.info(redact(`[dry-run] Email to ${this.config.to.join(', ')}: ${subject}\n${text}`));
+      return;
+    }
+
+    await this.transporter.sendMail({
+      from: this.config.from,
+      to: this.config.to,
+      subject: subject,
+      text: text,
+      html: `<p>${escapeHtml(subject)}</p>\n<p style="color: #888888;">${escapeHtml(text)}</p>`,
+    });
+  }
+}
+
+function escapeHtml(value: string): string {
+  return value
+    .replace(/&/g, '&amp;')
+    .replace(/</g, '&lt;')
+    .replace(/>/g, '&gt;')
+    .replace(/"/g, '&quot;')
+    .replace(/'/g, '&#39;');
+}
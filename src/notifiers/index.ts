@@ -0,0 +1,236 @@
+import * as core from '@actions/core';
+import { MonitorConfig, NotificationPayload, NotificationReceipt, Notifier, StatusCounts } from '../types';
+import { HttpRequester } from '../utils/http';
+import { redact } from '../utils/redact';
+import { EmailNotifier } from './email';
+import { GenericWebhookNotifier } from './webhook';
+import { SlackNotifier } from './slack';
+import { TeamsNotifier } from './teams';
+import { TelegramNotifier } from './telegram';
+
+/**
+ * A notification that no channel accepted, as opposed to a failed store API call
+ */
+export class NotificationError extends Error {
+  constructor(message: string) {
+    super(message);
+    this.name = 'NotificationError';
+  }
+}
+
+export interface ChannelCheckResult {
+  name: string;
+  skipped: boolean;
+  error?: string;
+}
+
+/**
+ * Sends every notification to all configured channels.
+ * Fails only when no channel accepted the notification.
+ */
+export class MultiNotifier implements Notifier {
+  // Deferred channels get the run's notifications as one digest from flushDigest()
+  private notifiers: { name: string; notifier: Notifier; deferred?: boolean }[] = [];
+  private pending: NotificationPayload[] = [];
+  // Sends rejected by a channel, including ones another channel accepted
+  private failedSends = 0;
+
+  constructor(config: MonitorConfig, http: HttpRequester) {
+    if (config.slack) {
+      this.notifiers.push({
+        name: 'Slack',
+        notifier: new SlackNotifier(config.slack, http),
+        deferred: config.slack.consolidateNotifications,
+      });
+    }
+
+    if (config.teams) {
+      this.notifiers.push({ name: 'Microsoft Teams', notifier: new TeamsNotifier(config.teams, http) });
+    }
+
+    if (config.email) {
+      this.notifiers.push({ name: 'email', notifier: new EmailNotifier(config.email) });
+    }
+
+    if (config.telegram) {
+      this.notifiers.push({ name: 'Telegram', notifier: new TelegramNotifier(config.telegram, http) });
+    }
+
+    if (config.genericWebhook) {
+      this.notifiers.push({ name: 'webhook', notifier: new GenericWebhookNotifier(config.genericWebhook, http) });
+    }
+
+    if (this.notifiers.length === 0) {
+      throw new Error('At least one notification channel must be configured');
+    }
+  }
+
+  /**
+   * Number of sends a channel rejected so far in this run
+   */
+  getFailedSendCount(): number {
+    return this.failedSends;
+  }
+
+  /**
+   * Validate every channel that supports it, failing on the first invalid one
+   */
+  async validate(): Promise<void> {
+    for (const { name, notifier } of this.notifiers) {
+      if (!notifier.validate) {
+        continue;
+      }
+
+      try {
+        await notifier.validate();
+      } catch (error) {
+        throw new Error(`${name} validation failed: ${redact(error instanceof Error ? error.message : error)}`);
+      }
+    }
+  }
+
+  /**
+   * Validate every channel, collecting each outcome instead of stopping at the first failure.
+   * Channels without a validation check are reported as skipped.
+   */
+  async validateAll(): Promise<ChannelCheckResult[]> {
+    const results: ChannelCheckResult[] = [];
+    for (const { name, notifier } of this.notifiers) {
+      if (!notifier.validate) {
+        results.push({ name, skipped: true });
+        continue;
+      }
+
+      try {
+        await notifier.validate();
+        results.push({ name, skipped: false });
+      } catch (error) {
+        results.push({ name, skipped: false, error: redact(error instanceof Error ? error.message : error) });
+      }
+    }
+    return results;
+  }
+
+  async sendNotification(payload: NotificationPayload): Promise<NotificationReceipt> {
+    const immediate = this.notifiers.filter(({ deferred }) => !deferred);
+    if (immediate.length < this.notifiers.length) {
+      // Digests aren't threaded, so no thread is kept for them
+      this.pending.push({ ...payload, slackThreadTs: undefined });
+    }
+
+    const results = await Promise.allSettled(immediate.map(({ notifier }) => notifier.sendNotification(payload)));
+
+    const failures: string[] = [];
+    const receipt: NotificationReceipt = {};
+    results.forEach((result, index) => {
+      const name = immediate[index].name;
+      if (result.status === 'rejected') {
+        core.warning(`Failed to send ${payload.platform} notification to ${name}: ${redact(result.reason)}`);
+        failures.push(name);
+        this.failedSends++;
+      } else {
+        core.info(`Sent ${payload.platform} notification to ${name}`);
+        Object.assign(receipt, result.value);
+      }
+    });
+
+    if (immediate.length > 0 && failures.length === immediate.length) {
+      throw new NotificationError(
+        `Failed to send ${payload.platform} notification to all channels (${failures.join(', ')})`
+      );
+    }
+
+    return receipt;
+  }
+
+  /**
+   * Send notifications held during quiet hours to every channel, as a digest where supported.
+   * Fails only when no channel accepted them.
+   */
+  async sendHeldNotifications(payloads: NotificationPayload[]): Promise<void> {
+    const results = await Promise.allSettled(
+      this.notifiers.map(async ({ notifier }) => {
+        if (notifier.sendDigest) {
+          await notifier.sendDigest(payloads, true);
+          return;
+        }
+        for (const payload of payloads) {
+          await notifier.sendNotification(payload);
+        }
+      })
+    );
+
+    const failures: string[] = [];
+    results.forEach((result, index) => {
+      const name = this.notifiers[index].name;
+      if (result.status === 'rejected') {
+        core.warning(`Failed to send notifications held during quiet hours to ${name}: ${redact(result.reason)}`);
+        failures.push(name);
+        this.failedSends++;
+      } else {
+        core.info(`Sent ${payloads.length} notification(s) held during quiet hours to ${name}`);
+      }
+    });
+
+    if (failures.length === this.notifiers.length) {
+      throw new NotificationError(
+        `Failed to send notifications held during quiet hours to all channels (${failures.join(', ')})`
+      );
+    }
+  }
+
+  /**
+   * Send the heartbeat to every channel that has one. The generic webhook keeps its payload
+   * schema to status notifications, so it gets none.
+   */
+  async sendHeartbeat(checkedCount: number): Promise<void> {
+    const channels = this.notifiers.filter(({ notifier }) => notifier.sendHeartbeat);
+    const results = await Promise.allSettled(channels.map(({ notifier }) => notifier.sendHeartbeat!(checkedCount)));
+
+    const failures: string[] = [];
+    results.forEach((result, index) => {
+      const name = channels[index].name;
+      if (result.status === 'rejected') {
+        core.warning(`Failed to send the heartbeat to ${name}: ${redact(result.reason)}`);
+        failures.push(name);
+      } else {
+        core.info(`Sent the heartbeat to ${name}`);
+      }
+    });
+
+    if (channels.length > 0 && failures.length === channels.length) {
+      throw new NotificationError(`Failed to send the heartbeat to all channels (${failures.join(', ')})`);
+    }
+  }
+
+  /**
+   * Send the notifications queued for deferred channels, as a digest when there are several
+   */
+  async flushDigest(statusCounts?: StatusCounts): Promise<void> {
+    const payloads = this.pending;
+    this.pending = [];
+    if (payloads.length === 0) {
+      return;
+    }
+
+    for (const { name, notifier, deferred } of this.notifiers) {
+      if (!deferred) {
+        continue;
+      }
+
+      try {
+        if (payloads.length > 1 && notifier.sendDigest) {
+          await notifier.sendDigest(payloads, false, statusCounts);
+        } else {
+          for (const payload of payloads) {
+            await notifier.sendNotification(payload);
+          }
+        }
+      } catch (error) {
+        this.failedSends++;
+        throw new NotificationError(`Failed to send ${payloads.length} consolidated notification(s) to ${name}: ${redact(error)}`);
+      }
+      core.info(`Sent ${payloads.length} consolidated notification(s) to ${name}`);
+    }
+  }
+}
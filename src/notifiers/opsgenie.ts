@@ -0,0 +1,91 @@
+import * as core from '@actions/core';
+import { Incident, OpsGenieConfig, OpsGenieRegion } from '../types';
+import { HttpRequester } from '../utils/http';
+import { redact } from '../utils/redact';
+import { formatStatus } from '../utils/status';
+
+const ALERTS_API_URLS: Record<OpsGenieRegion, string> = {
+  us: 'https://api.opsgenie.com/v2/alerts',
+  eu: 'https://api.eu.opsgenie.com/v2/alerts',
+};
+
+// OpsGenie truncates longer alert messages
+const MAX_MESSAGE_LENGTH = 130;
+
+export type OpsGeniePriority = 'P1' | 'P2' | 'P3' | 'P4' | 'P5';
+
+/**
+ * Creates and closes OpsGenie alerts for critical review states through the Alert API
+ */
+export class OpsGenieClient {
+  private config: OpsGenieConfig;
+  private http: HttpRequester;
+
+  constructor(config: OpsGenieConfig, http: HttpRequester) {
+    this.config = config;
+    this.http = http;
+  }
+
+  async createOpsGenieAlert(incident: Incident, priority: OpsGeniePriority): Promise<void> {
+    await this.sendRequest('create', incident, ALERTS_API_URLS[this.config.region], {
+      message: `${incident.platform} review ${formatStatus(incident.status)}: ${incident.subject} ${incident.version}`.slice(
+        0,
+        MAX_MESSAGE_LENGTH
+      ),
+      alias: getAlias(incident),
+      source: 'store-review-monitor',
+      priority: priority,
+      tags: ['store-review-monitor', incident.platform],
+      details: {
+        platform: incident.platform,
+        subject: incident.subject,
+        version: incident.version,
+        status: incident.status,
+        ...(incident.previousStatus ? { previousStatus: incident.previousStatus } : {}),
+      },
+    });
+  }
+
+  async closeOpsGenieAlert(incident: Incident): Promise<void> {
+    await this.sendRequest(
+      'close',
+      incident,
+      `${ALERTS_API_URLS[this.config.region]}/${encodeURIComponent(getAlias(incident))}/close?identifierType=alias`,
+      {
+        source: 'store-review-monitor',
+        note: `Recovered: ${formatStatus(incident.status)}`,
+      }
+    );
+  }
+
+  private async sendRequest(
+    action: string,
+    incident: Incident,
+    url: string,
+    body: Record<string, unknown>
+  ): Promise<void> {
+    if (this.config.dryRun) {
+      core.info(redact(`[dry-run] OpsGenie ${action} request to ${url}: ${JSON.stringify(body)}`));
+      return;
+    }
+
+    // Requests are processed asynchronously; 202 only means the request was accepted
+    await this.http.request({
+      method: 'post',
+      url: url,
+      data: body,
+      headers: {
+        Authorization: `GenieKey ${this.config.apiKey}`,
+        'Content-Type': 'application/json',
+      },
+    });
+    core.info(`Sent OpsGenie ${action} request (${getAlias(incident)})`);
+  }
+}
+
+/**
+ * Stable alias, so repeated runs deduplicate into one alert and recovery closes it
+ */
+function getAlias(incident: Incident): string {
+  return `store-review-monitor/${incident.platform}/${incident.subject}/${incident.version}`;
+}
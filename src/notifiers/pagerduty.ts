@@ -0,0 +1,72 @@
+import * as core from '@actions/core';
+import { Incident, PagerDutyConfig } from '../types';
+import { HttpRequester } from '../utils/http';
+import { redact } from '../utils/redact';
+import { formatStatus } from '../utils/status';
+
+const EVENTS_API_URL = 'https://events.pagerduty.com/v2/enqueue';
+
+/**
+ * Opens and resolves PagerDuty incidents for rejections through the Events API v2
+ */
+export class PagerDutyClient {
+  private config: PagerDutyConfig;
+  private http: HttpRequester;
+
+  constructor(config: PagerDutyConfig, http: HttpRequester) {
+    this.config = config;
+    this.http = http;
+  }
+
+  async triggerPagerDutyEvent(incident: Incident): Promise<void> {
+    await this.sendEvent({
+      routing_key: this.config.routingKey,
+      event_action: 'trigger',
+      dedup_key: getDedupKey(incident),
+      payload: {
+        summary: `${incident.platform} review rejected: ${incident.subject} ${incident.version} (${formatStatus(incident.status)})`,
+        source: 'store-review-monitor',
+        severity: 'critical',
+        custom_details: {
+          platform: incident.platform,
+          subject: incident.subject,
+          version: incident.version,
+          status: incident.status,
+          previousStatus: incident.previousStatus,
+        },
+      },
+    });
+  }
+
+  async resolvePagerDutyEvent(incident: Incident): Promise<void> {
+    await this.sendEvent({
+      routing_key: this.config.routingKey,
+      event_action: 'resolve',
+      dedup_key: getDedupKey(incident),
+    });
+  }
+
+  private async sendEvent(event: Record<string, unknown>): Promise<void> {
+    if (this.config.dryRun) {
+      core.info(redact(`[dry-run] PagerDuty event: ${JSON.stringify({ ...event, routing_key: '***' })}`));
+      return;
+    }
+
+    await this.http.request({
+      method: 'post',
+      url: EVENTS_API_URL,
+      data: event,
+      headers: {
+        'Content-Type': 'application/json',
+      },
+    });
+    core.info(`Sent PagerDuty ${event.event_action} event (${event.dedup_key})`);
+  }
+}
+
+/**
+ * Stable key so the resolve event on recovery closes the incident opened by the rejection
+ */
+function getDedupKey(incident: Incident): string {
+  return `store-review-monitor/${incident.platform}/${incident.subject}/${incident.version}`;
+}
@@ -1,26 +1,60 @@
-import { IncomingWebhook } from '@slack/webhook';
-import { WebClient } from '@slack/web-api';
-import { NotificationPayload, SlackConfig } from '../types';
-import { getMessages, Language } from '../types/i18n';
-
-export class SlackNotifier {
-  private webhook?: IncomingWebhook;
-  private webClient?: WebClient;
+import * as core from '@actions/core';
+import axios from 'axios';
+import { createHash } from 'crypto';
+import { NotificationPayload, NotificationReceipt, Notifier, SlackConfig, StatusCounts } from '../types';
+import { getMessages, Language, Messages } from '../types/i18n';
+import { HttpRequester, parseRetryAfter, sleep } from '../utils/http';
+import { redact } from '../utils/redact';
+import { formatCheckedAt, formatReleaseInfo, formatStatus, formatTimestamp, formatTitle, getStatusColor, getStatusEmoji } from '../utils/status';
+import { getBuildUrl, renderTemplate } from '../utils/template';
+import { getVersionLabel } from '../version';
+
+const SLACK_API_URL = 'https://slack.com/api';
+
+// action_id values the Slack app's interactivity handler receives on button clicks
+const ACKNOWLEDGE_ACTION_ID = 'store_review_acknowledge';
+const OPEN_CONSOLE_ACTION_ID = 'store_review_open_console';
+
+// Channels per conversations.list page; Slack may return fewer
+const CONVERSATIONS_PAGE_LIMIT = 1000;
+
+// Retries of a rate-limited Slack request, on top of HttpClient's own retries of HTTP 429,
+// so a burst of notifications waits out the per-channel limit instead of being lost
+const RATE_LIMIT_RETRIES = 3;
+const RATE_LIMIT_BASE_DELAY_MS = 1000;
+const RATE_LIMIT_MAX_DELAY_MS = 60000;
+
+interface SlackApiResponse {
+  ok: boolean;
+  error?: string;
+  [key: string]: unknown;
+}
+
+/**
+ * Web API call answered with a rate_limited error
+ */
+class SlackRateLimitError extends Error {
+  readonly retryAfterMs?: number;
+
+  constructor(message: string, retryAfterMs?: number) {
+    super(message);
+    this.name = 'SlackRateLimitError';
+    this.retryAfterMs = retryAfterMs;
+  }
+}
+
+export class SlackNotifier implements Notifier {
   private config: SlackConfig;
+  private http: HttpRequester;
   private language: Language;
+  // #name -> channel ID, resolved once per run
+  private channelIds = new Map<string, string>();
 
-  constructor(config: SlackConfig) {
+  constructor(config: SlackConfig, http: HttpRequester) {
     this.config = config;
+    this.http = http;
     this.language = config.language || 'en';
 
-    if (config.webhookUrl) {
-      this.webhook = new IncomingWebhook(config.webhookUrl);
-    }
-
-    if (config.botToken) {
-      this.webClient = new WebClient(config.botToken);
-    }
-
     if (!config.webhookUrl && !config.botToken) {
       throw new Error('Either webhookUrl or botToken must be provided for Slack notifications');
     }
@@ -30,28 +64,237 @@ export class SlackNotifier {
     }
   }
 
-  async sendNotification(payload: NotificationPayload): Promise<void> {
+  async validate(): Promise<void> {
+    if (this.config.botToken) {
+      const result = await this.callWebApi('auth.test', {});
+      core.info(`Slack bot token is valid (team: ${result.team}, user: ${result.user})`);
+      return;
+    }
+
+    if (this.config.webhookUrl) {
+      // An empty payload is rejected with 400 "no_text" by a valid webhook without posting anything,
+      // while revoked or unknown webhooks answer 403/404/410
+      const response = await this.http.request<string>({
+        method: 'post',
+        url: this.config.webhookUrl,
+        data: {},
+        headers: {
+          'Content-Type': 'application/json',
+        },
+        validateStatus: () => true,
+      });
+
+      if (response.status === 400 || (response.status >= 200 && response.status < 300)) {
+        core.info('Slack webhook URL is reachable');
+        return;
+      }
+
+      throw new Error(`Slack webhook validation failed (HTTP ${response.status}: ${response.data})`);
+    }
+  }
+
+  async sendNotification(payload: NotificationPayload): Promise<NotificationReceipt | void> {
     const messages = getMessages(this.language);
-    const color = this.getStatusColor(payload.currentStatus);
-    const emoji = this.getStatusEmoji(payload.currentStatus);
+    const color = getStatusColor(payload.currentStatus);
+    const emoji = getStatusEmoji(payload.currentStatus);
+    const mentionText = this.getMentionText([payload]);
+    const checkedAt = formatCheckedAt(new Date());
 
-    // Build mention text
-    const mentionText = this.config.mentions && this.config.mentions.length > 0
-      ? this.config.mentions.map(m => `<@${m}>`).join(' ') + ' '
-      : '';
+    const regressionPrefix = payload.regression ? `⚠️ ${messages.regression}: ` : '';
+    const headerText = `${regressionPrefix}${payload.nowLive ? '🎉' : emoji} ${formatTitle(payload, messages)}`;
+    const fallbackText = messages.fallbackMessage(payload.platform, formatStatus(payload.currentStatus));
 
-    const headerText = `${emoji} ${payload.platform} ${messages.reviewStatusUpdate}`;
-    const fallbackText = messages.fallbackMessage(payload.platform, this.formatStatus(payload.currentStatus));
+    const message = this.config.template
+      ? {
+          // A custom template replaces the default layout entirely
+          text: this.renderCustomTemplate(payload, mentionText, checkedAt),
+        }
+      : {
+          text: mentionText + headerText,
+          blocks: [
+            {
+              type: 'header',
+              text: {
+                type: 'plain_text',
+                text: headerText,
+                emoji: true,
+              },
+            },
+            ...this.buildDetailBlocks(payload, messages, !this.config.webhookUrl),
+            buildContextBlock(messages, checkedAt, this.renderFooterText(payload)),
+          ],
+          attachments: [
+            {
+              color: color,
+              fallback: fallbackText,
+            },
+          ],
+        };
 
-    const blocks = [
-      {
-        type: 'header',
-        text: {
-          type: 'plain_text',
-          text: headerText,
-          emoji: true,
+    if (this.config.dryRun) {
+      const target = this.config.webhookUrl ? 'webhook' : `chat.postMessage (${this.resolveChannel(payload)})`;
+      core.info(redact(`[dry-run] Slack ${target} payload: ${JSON.stringify(message)}`));
+      return;
+    }
+
+    // Threads and updated messages live in the primary channel, so severity-routed messages are posted standalone
+    const channel = this.resolveChannel(payload);
+    const tracked =
+      !this.config.webhookUrl && (this.config.threadByVersion || this.config.updateInPlace) && channel === this.config.channel;
+    const threadTs = tracked ? payload.slackThreadTs : undefined;
+
+    // A re-run or retry of a notification the bot already posted would duplicate it
+    const messageKey = this.config.webhookUrl ? undefined : getMessageKey(payload);
+    if (messageKey && messageKey === payload.slackLastMessageKey) {
+      core.info(
+        `Skipping Slack chat.postMessage: ${payload.platform} ${payload.version} (${payload.currentStatus}) was already posted`
+      );
+      return { slackThreadTs: threadTs, slackMessageKey: messageKey };
+    }
+
+    if (this.config.updateInPlace && threadTs && (await this.updateMessage(message, channel, threadTs))) {
+      await this.uploadLongRejectionReason(payload, channel, threadTs);
+      return { slackThreadTs: threadTs, slackMessageKey: messageKey };
+    }
+
+    const ts = await this.postMessage(message, channel, this.config.updateInPlace ? undefined : threadTs);
+    if (ts) {
+      await this.uploadLongRejectionReason(payload, channel, threadTs || ts);
+    }
+
+    // Replies keep the thread's root ts, which is what later updates reply to
+    return { slackThreadTs: tracked ? threadTs || ts : undefined, slackMessageKey: messageKey };
+  }
+
+  /**
+   * Send several notifications as one message, with a colored attachment per app/track.
+   * Posted to the primary channel without threading.
+   */
+  async sendDigest(payloads: NotificationPayload[], heldDuringQuietHours = false, statusCounts?: StatusCounts): Promise<void> {
+    const messages = getMessages(this.language);
+    const mentionText = this.getMentionText(payloads);
+    const checkedAt = formatCheckedAt(new Date());
+    const countsText = this.config.statusCounts && statusCounts ? formatStatusCounts(statusCounts, messages) : '';
+
+    const regressionPrefix = payloads.some((payload) => payload.regression) ? `⚠️ ${messages.regression}: ` : '';
+    const headerText = `${regressionPrefix}📋 ${messages.reviewStatusUpdate}`;
+    const summaryText = heldDuringQuietHours
+      ? messages.quietHoursSummary(payloads.length)
+      : messages.digestSummary(payloads.length);
+
+    const message = this.config.template
+      ? {
+          text: payloads.map((payload) => this.renderCustomTemplate(payload, mentionText, checkedAt)).join('\n\n'),
+        }
+      : {
+          text: `${mentionText}${headerText}: ${summaryText}${countsText ? ` (${countsText})` : ''}`,
+          blocks: [
+            {
+              type: 'header',
+              text: {
+                type: 'plain_text',
+                text: headerText,
+                emoji: true,
+              },
+            },
+            ...(heldDuringQuietHours
+              ? [
+                  {
+                    type: 'section',
+                    text: {
+                      type: 'mrkdwn',
+                      text: `🌙 ${summaryText}`,
+                    },
+                  },
+                ]
+              : []),
+            ...(countsText
+              ? [
+                  {
+                    type: 'section',
+                    text: {
+                      type: 'mrkdwn',
+                      text: `📊 ${countsText}`,
+                    },
+                  },
+                ]
+              : []),
+            buildContextBlock(messages, checkedAt, this.renderFooterText()),
+          ],
+          attachments: payloads.map((payload) => ({
+            color: getStatusColor(payload.currentStatus),
+            fallback: messages.fallbackMessage(payload.platform, formatStatus(payload.currentStatus)),
+            blocks: [
+              {
+                type: 'section',
+                text: {
+                  type: 'mrkdwn',
+                  text: `*${payload.regression ? `⚠️ ${messages.regression}: ` : ''}${getStatusEmoji(payload.currentStatus)} ${payload.platform}${payload.appName ? ` · ${payload.appName}` : ''}*`,
+                },
+              },
+              ...this.buildDetailBlocks(payload, messages),
+            ],
+          })),
+        };
+
+    if (this.config.dryRun) {
+      const target = this.config.webhookUrl ? 'webhook' : `chat.postMessage (${this.config.channel})`;
+      core.info(redact(`[dry-run] Slack ${target} digest payload: ${JSON.stringify(message)}`));
+      return;
+    }
+
+    await this.postMessage(message, this.config.channel);
+  }
+
+  /**
+   * Post the heartbeat to the default channel, without mentions
+   */
+  async sendHeartbeat(checkedCount: number): Promise<void> {
+    const messages = getMessages(this.language);
+    const text = `💚 ${messages.heartbeat(checkedCount)}`;
+    const message = {
+      text: text,
+      blocks: [
+        {
+          type: 'section',
+          text: {
+            type: 'mrkdwn',
+            text: text,
+          },
         },
-      },
+        buildContextBlock(messages, formatCheckedAt(new Date()), this.renderFooterText()),
+      ],
+    };
+
+    if (this.config.dryRun) {
+      const target = this.config.webhookUrl ? 'webhook' : `chat.postMessage (${this.config.channel})`;
+      core.info(redact(`[dry-run] Slack ${target} heartbeat payload: ${JSON.stringify(message)}`));
+      return;
+    }
+
+    await this.postMessage(message, this.config.channel);
+  }
+
+  /**
+   * Build mention text, optionally only when one of the notifications is a rejection or regression
+   */
+  private getMentionText(payloads: NotificationPayload[]): string {
+    const mentionable =
+      !this.config.mentionsOnRejectionOnly ||
+      payloads.some((payload) => getStatusColor(payload.currentStatus) === 'danger' || !!payload.regression);
+    return mentionable && this.config.mentions && this.config.mentions.length > 0
+      ? this.config.mentions.map(formatMention).join(' ') + ' '
+      : '';
+  }
+
+  /**
+   * Status fields, rejection reason and console link shown for one app/track. With snippet,
+   * a shortened rejection reason says the full text is attached in the thread.
+   */
+  private buildDetailBlocks(payload: NotificationPayload, messages: Messages, snippet = false): object[] {
+    const releaseInfo = formatReleaseInfo(payload, messages);
+
+    return [
       {
         type: 'section',
         fields: [
@@ -65,18 +308,105 @@ export class SlackNotifier {
           },
           {
             type: 'mrkdwn',
-            text: `*${messages.currentStatus}:*\n${this.formatStatus(payload.currentStatus)}`,
+            text: `*${messages.currentStatus}:*\n${formatStatus(payload.currentStatus)}`,
           },
           ...(payload.previousStatus
             ? [
                 {
                   type: 'mrkdwn',
-                  text: `*${messages.previousStatus}:*\n${this.formatStatus(payload.previousStatus)}`,
+                  text: `*${messages.previousStatus}:*\n${formatStatus(payload.previousStatus)}`,
+                },
+              ]
+            : []),
+          ...(payload.rolloutPercentage !== undefined
+            ? [
+                {
+                  type: 'mrkdwn',
+                  text: `*${messages.rollout}:*\n${payload.rolloutPercentage}%`,
+                },
+              ]
+            : []),
+          ...(releaseInfo
+            ? [
+                {
+                  type: 'mrkdwn',
+                  text: `*${messages.release}:*\n${releaseInfo}`,
+                },
+              ]
+            : []),
+          ...(payload.submittedAt
+            ? [
+                {
+                  type: 'mrkdwn',
+                  text: `*${messages.submitted}:*\n${formatTimestamp(payload.submittedAt)}`,
+                },
+              ]
+            : []),
+          ...(payload.daysInReview !== undefined
+            ? [
+                {
+                  type: 'mrkdwn',
+                  text: `*${messages.daysInReview}:*\n${payload.daysInReview}`,
                 },
               ]
             : []),
         ],
       },
+      ...(payload.actionRequired
+        ? [
+            {
+              type: 'section',
+              text: {
+                type: 'mrkdwn',
+                text: `🚀 *${messages.actionRequiredRelease}*`,
+              },
+            },
+          ]
+        : []),
+      ...(payload.rolloutCompleted
+        ? [
+            {
+              type: 'section',
+              text: {
+                type: 'mrkdwn',
+                text: `🎉 *${messages.rolloutCompleted}*`,
+              },
+            },
+          ]
+        : []),
+      ...(payload.nowLive
+        ? [
+            {
+              type: 'section',
+              text: {
+                type: 'mrkdwn',
+                text: `🎉 *${messages.nowLive}*${payload.storeUrl ? ` <${payload.storeUrl}|${messages.viewListing}>` : ''}`,
+              },
+            },
+          ]
+        : []),
+      ...(payload.credentialsInvalid
+        ? [
+            {
+              type: 'section',
+              text: {
+                type: 'mrkdwn',
+                text: `🔑 *${messages.credentialsInvalid(payload.platform)}*`,
+              },
+            },
+          ]
+        : []),
+      ...(getStatusColor(payload.currentStatus) === 'danger'
+        ? [
+            {
+              type: 'section',
+              text: {
+                type: 'mrkdwn',
+                text: `*${messages.rejectionReason}:*\n${this.formatRejectionReason(payload, messages, snippet)}`,
+              },
+            },
+          ]
+        : []),
       ...(payload.appName
         ? [
             {
@@ -90,109 +420,413 @@ export class SlackNotifier {
             },
           ]
         : []),
-      {
-        type: 'context',
-        elements: [
-          {
-            type: 'mrkdwn',
-            text: `${messages.checkedAt}: ${new Date().toISOString()}`,
-          },
-        ],
-      },
+      ...(payload.reminderHours !== undefined
+        ? [
+            {
+              type: 'section',
+              text: {
+                type: 'mrkdwn',
+                text: `⏰ ${messages.statusReminder(payload.reminderHours)}`,
+              },
+            },
+          ]
+        : []),
+      ...(payload.monitoringLapsedHours !== undefined
+        ? [
+            {
+              type: 'section',
+              text: {
+                type: 'mrkdwn',
+                text: `⚠️ ${messages.monitoringLapsed(payload.monitoringLapsedHours)}`,
+              },
+            },
+          ]
+        : []),
+      ...(payload.flapping
+        ? [
+            {
+              type: 'section',
+              text: {
+                type: 'mrkdwn',
+                text: `🔁 ${messages.flappingDetected(payload.flapping.maxTransitions, payload.flapping.windowHours)}`,
+              },
+            },
+          ]
+        : []),
+      ...this.buildActionsBlocks(payload, messages),
     ];
+  }
 
-    if (this.webhook) {
-      // Use webhook
-      const message = {
-        text: mentionText + headerText,
-        blocks: blocks,
-        attachments: [
-          {
-            color: color,
-            fallback: fallbackText,
-          },
-        ],
-      };
-
-      await this.webhook.send(message);
-    } else if (this.webClient && this.config.channel) {
-      // Use Web API with bot token
-      await this.webClient.chat.postMessage({
-        channel: this.config.channel,
-        text: mentionText + headerText,
-        blocks: blocks,
-        attachments: [
-          {
-            color: color,
-            fallback: fallbackText,
+  /**
+   * Console link button, plus an Acknowledge button when slack-enable-actions is on. Clicks
+   * on either are sent to the Slack app's interactivity Request URL along with the action_id.
+   */
+  private buildActionsBlocks(payload: NotificationPayload, messages: Messages): object[] {
+    const elements = [
+      ...(this.config.enableActions
+        ? [
+            {
+              type: 'button',
+              action_id: ACKNOWLEDGE_ACTION_ID,
+              style: 'primary',
+              text: {
+                type: 'plain_text',
+                text: messages.acknowledge,
+              },
+              // Identifies the notification for the interactivity handler (max 2000 characters)
+              value: JSON.stringify({
+                platform: payload.platform,
+                appName: payload.appName,
+                version: payload.version,
+                status: payload.currentStatus,
+              }).slice(0, 2000),
+            },
+          ]
+        : []),
+      ...((this.config.includeConsoleLinks || this.config.enableActions) && payload.consoleUrl
+        ? [
+            {
+              type: 'button',
+              ...(this.config.enableActions ? { action_id: OPEN_CONSOLE_ACTION_ID } : {}),
+              text: {
+                type: 'plain_text',
+                text: messages.openConsole(
+                  payload.platform === 'Google Play'
+                    ? 'Google Play Console'
+                    : payload.platform === 'Amazon Appstore'
+                      ? 'Amazon Developer Console'
+                      : payload.platform === 'Huawei AppGallery'
+                        ? 'AppGallery Connect'
+                        : 'App Store Connect'
+                ),
+              },
+              url: payload.consoleUrl,
+            },
+          ]
+        : []),
+    ];
+
+    return elements.length > 0 ? [{ type: 'actions', elements: elements }] : [];
+  }
+
+  /**
+   * Render slack-footer-text. Digests span several apps, so only BuildUrl is set for them.
+   */
+  private renderFooterText(payload?: NotificationPayload): string | undefined {
+    if (!this.config.footerText) {
+      return undefined;
+    }
+    return renderTemplate(this.config.footerText, {
+      Platform: payload?.platform,
+      AppName: payload?.appName,
+      Version: payload?.version,
+      BuildUrl: getBuildUrl(),
+    });
+  }
+
+  private renderCustomTemplate(payload: NotificationPayload, mentionText: string, checkedAt: string): string {
+    const releaseInfo = formatReleaseInfo(payload, getMessages(this.language));
+    return renderTemplate(this.config.template || '', {
+      Platform: payload.platform,
+      Version: payload.version,
+      CurrentStatus: formatStatus(payload.currentStatus),
+      PreviousStatus: payload.previousStatus ? formatStatus(payload.previousStatus) : undefined,
+      Emoji: getStatusEmoji(payload.currentStatus),
+      CheckedAt: checkedAt,
+      AppName: payload.appName,
+      RejectionReason: payload.rejectionReason,
+      Release: releaseInfo,
+      Rollout: payload.rolloutPercentage !== undefined ? `${payload.rolloutPercentage}%` : undefined,
+      Submitted: payload.submittedAt ? formatTimestamp(payload.submittedAt) : undefined,
+      DaysInReview: payload.daysInReview !== undefined ? `${payload.daysInReview}` : undefined,
+      ConsoleUrl: payload.consoleUrl,
+      Mentions: mentionText.trim(),
+    });
+  }
+
+  /**
+   * Post through the webhook, or chat.postMessage with the bot token. Returns the message ts
+   * for bot token posts.
+   */
+  private async postMessage(message: object, channel: string | undefined, threadTs?: string): Promise<string | undefined> {
+    if (this.config.webhookUrl) {
+      await withRateLimitRetry('webhook', () =>
+        this.http.request({
+          method: 'post',
+          url: this.config.webhookUrl,
+          data: message,
+          headers: {
+            'Content-Type': 'application/json',
           },
-        ],
+        })
+      );
+      return undefined;
+    }
+
+    const channelId = channel ? await this.resolveChannelId(channel) : channel;
+    let result: SlackApiResponse;
+    try {
+      result = await this.callWebApi('chat.postMessage', {
+        channel: channelId,
+        ...(threadTs ? { thread_ts: threadTs } : {}),
+        ...message,
       });
+    } catch (error) {
+      if (error instanceof Error && /channel_not_found|not_in_channel/.test(error.message)) {
+        throw new Error(
+          `${error.message}: the bot can't post to ${channel}. Invite it with /invite @<bot name> in the channel, or check slack-channel`
+        );
+      }
+      throw error;
     }
+    return result.ts as string;
   }
 
-  private getStatusColor(status: string): string {
-    const statusLower = status.toLowerCase();
+  /**
+   * Rejection reason cut to slack-snippet-threshold characters, since Block Kit rejects longer
+   * section text, with a note saying where the full text is instead of truncating silently
+   */
+  private formatRejectionReason(payload: NotificationPayload, messages: Messages, snippet: boolean): string {
+    const reason = payload.rejectionReason || messages.rejectionReasonUnavailable(payload.platform);
+    if (reason.length <= this.config.snippetThreshold) {
+      return reason;
+    }
+    const note = snippet ? messages.rejectionReasonInThread : messages.rejectionReasonTruncated;
+    return `${reason.slice(0, this.config.snippetThreshold)}…\n_${note}_`;
+  }
 
-    if (
-      statusLower.includes('approved') ||
-      statusLower.includes('ready_for_sale') ||
-      statusLower.includes('completed') ||
-      statusLower.includes('pending_developer_release')
-    ) {
-      return 'good'; // Green
+  /**
+   * Attach a rejection reason that was shortened in the message as a text snippet in its
+   * thread, through Slack's external upload flow (files.upload has been retired). A failed
+   * upload only warns, since the message itself was posted.
+   */
+  private async uploadLongRejectionReason(
+    payload: NotificationPayload,
+    channel: string | undefined,
+    threadTs: string
+  ): Promise<void> {
+    const reason = payload.rejectionReason;
+    if (!reason || reason.length <= this.config.snippetThreshold || getStatusColor(payload.currentStatus) !== 'danger') {
+      return;
     }
 
-    if (
-      statusLower.includes('rejected') ||
-      statusLower.includes('invalid')
-    ) {
-      return 'danger'; // Red
+    const title = `${payload.platform} ${payload.version} rejection reason`;
+    try {
+      const upload = await this.callWebApi(
+        'files.getUploadURLExternal',
+        { filename: `${title.replace(/[^\w.-]+/g, '-')}.txt`, length: Buffer.byteLength(reason, 'utf-8') },
+        'get'
+      );
+      await withRateLimitRetry('file upload', () =>
+        this.http.request({
+          method: 'post',
+          url: upload.upload_url as string,
+          data: reason,
+          headers: {
+            'Content-Type': 'text/plain; charset=utf-8',
+          },
+        })
+      );
+
+      const channelId = channel ? await this.resolveChannelId(channel) : channel;
+      await this.callWebApi('files.completeUploadExternal', {
+        files: [{ id: upload.file_id, title: title }],
+        channel_id: channelId,
+        thread_ts: threadTs,
+      });
+    } catch (error) {
+      core.warning(
+        `Failed to upload the full rejection reason to Slack (${redact(error)}). Add the files:write scope to the Slack app`
+      );
     }
+  }
 
-    if (
-      statusLower.includes('in_review') ||
-      statusLower.includes('processing')
-    ) {
-      return 'warning'; // Yellow
+  /**
+   * Replace an earlier bot message with chat.update. Returns false when the message can no
+   * longer be updated (e.g. it was deleted), so a new one is posted instead.
+   */
+  private async updateMessage(message: object, channel: string | undefined, ts: string): Promise<boolean> {
+    const channelId = channel ? await this.resolveChannelId(channel) : channel;
+    try {
+      await this.callWebApi('chat.update', { channel: channelId, ts: ts, ...message });
+      return true;
+    } catch (error) {
+      if (error instanceof Error && /message_not_found|cant_update_message|edit_window_closed/.test(error.message)) {
+        core.warning(`${error.message}, posting a new Slack message instead`);
+        return false;
+      }
+      throw error;
     }
+  }
 
-    return '#808080'; // Gray
+  /**
+   * Resolve a #name to its channel ID through conversations.list, since private channels and
+   * some workspaces only accept IDs. Other values are taken to be IDs already.
+   */
+  private async resolveChannelId(channel: string): Promise<string> {
+    if (!channel.startsWith('#')) {
+      return channel;
+    }
+    const cached = this.channelIds.get(channel);
+    if (cached) {
+      return cached;
+    }
+
+    const name = channel.slice(1);
+    let cursor: string | undefined;
+    do {
+      let result: SlackApiResponse;
+      try {
+        result = await this.callWebApi(
+          'conversations.list',
+          {
+            types: 'public_channel,private_channel',
+            exclude_archived: true,
+            limit: CONVERSATIONS_PAGE_LIMIT,
+            ...(cursor ? { cursor } : {}),
+          },
+          'get'
+        );
+      } catch (error) {
+        throw new Error(
+          `Couldn't resolve Slack channel ${channel} to an ID (${error instanceof Error ? error.message : error}). Add the channels:read and groups:read scopes to the Slack app, or set slack-channel to the channel ID`
+        );
+      }
+
+      const match = ((result.channels as any[]) || []).find((c) => c.name === name);
+      if (match) {
+        core.info(`Resolved Slack channel ${channel} to ${match.id}`);
+        this.channelIds.set(channel, match.id);
+        return match.id;
+      }
+      cursor = (result.response_metadata as any)?.next_cursor || undefined;
+    } while (cursor);
+
+    throw new Error(
+      `Slack channel ${channel} wasn't found. Private channels are only visible to the bot once it's a member: invite it with /invite @<bot name> in ${channel}, or set slack-channel to the channel ID`
+    );
   }
 
-  private getStatusEmoji(status: string): string {
-    const statusLower = status.toLowerCase();
+  /**
+   * Route rejections and regressions to the severity channel when one is configured
+   */
+  private resolveChannel(payload: NotificationPayload): string | undefined {
+    const severe = payload.regression || getStatusColor(payload.currentStatus) === 'danger';
+    return severe && this.config.rejectedChannel ? this.config.rejectedChannel : this.config.channel;
+  }
 
-    if (
-      statusLower.includes('approved') ||
-      statusLower.includes('ready_for_sale') ||
-      statusLower.includes('completed') ||
-      statusLower.includes('pending_developer_release')
-    ) {
-      return '✅';
-    }
+  /**
+   * Call a Slack Web API method, which reports failures as ok: false with HTTP 200.
+   * Read methods such as conversations.list take their arguments as query parameters.
+   */
+  private callWebApi(method: string, body: object, httpMethod: 'get' | 'post' = 'post'): Promise<SlackApiResponse> {
+    return withRateLimitRetry(method, async () => {
+      const response = await this.http.request<SlackApiResponse>({
+        method: httpMethod,
+        url: `${SLACK_API_URL}/${method}`,
+        ...(httpMethod === 'get' ? { params: body } : { data: body }),
+        headers: {
+          'Authorization': `Bearer ${this.config.botToken}`,
+          'Content-Type': 'application/json; charset=utf-8',
+        },
+      });
 
-    if (
-      statusLower.includes('rejected') ||
-      statusLower.includes('invalid')
-    ) {
-      return '❌';
-    }
+      if (!response.data.ok) {
+        const message = `Slack ${method} failed: ${response.data.error || 'unknown error'}`;
+        if (response.data.error === 'rate_limited' || response.data.error === 'ratelimited') {
+          throw new SlackRateLimitError(message, parseRetryAfter(response.headers?.['retry-after']));
+        }
+        throw new Error(message);
+      }
+      return response.data;
+    });
+  }
+}
 
-    if (
-      statusLower.includes('in_review') ||
-      statusLower.includes('processing')
-    ) {
-      return '⏳';
+/**
+ * Run a Slack request, waiting and retrying while Slack answers HTTP 429 or rate_limited.
+ * Waits for Retry-After when given, and backs off exponentially otherwise.
+ */
+async function withRateLimitRetry<T>(description: string, send: () => Promise<T>): Promise<T> {
+  for (let attempt = 0; ; attempt++) {
+    try {
+      return await send();
+    } catch (error) {
+      let retryAfterMs: number | undefined;
+      if (error instanceof SlackRateLimitError) {
+        retryAfterMs = error.retryAfterMs;
+      } else if (axios.isAxiosError(error) && error.response?.status === 429) {
+        retryAfterMs = parseRetryAfter(error.response.headers?.['retry-after']);
+      } else {
+        throw error;
+      }
+      if (attempt >= RATE_LIMIT_RETRIES) {
+        throw error;
+      }
+
+      const delay = Math.min(retryAfterMs ?? RATE_LIMIT_BASE_DELAY_MS * 2 ** attempt, RATE_LIMIT_MAX_DELAY_MS);
+      core.info(`Slack ${description} was rate limited, retrying in ${delay}ms (attempt ${attempt + 1}/${RATE_LIMIT_RETRIES})`);
+      await sleep(delay);
     }
+  }
+}
 
-    return 'ℹ️';
+/**
+ * Deterministic key of a notification's platform, app, version and status, so the same
+ * notification maps to the same key on every run
+ */
+function getMessageKey(payload: NotificationPayload): string {
+  const app = payload.event?.appId || payload.event?.packageName || '';
+  return createHash('sha256')
+    .update([payload.platform, app, payload.version, payload.currentStatus].join('\n'))
+    .digest('hex')
+    .slice(0, 16);
+}
+
+/**
+ * One-line pulse of a run, e.g. "3 rejected, 2 ready for sale, 5 unchanged", with the most
+ * frequent statuses first
+ */
+function formatStatusCounts(counts: StatusCounts, messages: Messages): string {
+  const parts = Object.entries(counts.changed)
+    .sort(([, a], [, b]) => b - a)
+    .map(([status, count]) => `${count} ${formatStatus(status).toLowerCase()}`);
+  if (counts.unchanged > 0) {
+    parts.push(messages.unchangedCount(counts.unchanged));
+  }
+  if (counts.failed > 0) {
+    parts.push(messages.failedCount(counts.failed));
   }
+  return parts.join(', ');
+}
+
+/**
+ * Footer with the check time and the action version, followed by slack-footer-text
+ */
+function buildContextBlock(messages: Messages, checkedAt: string, footerText?: string): object {
+  return {
+    type: 'context',
+    elements: [
+      {
+        type: 'mrkdwn',
+        text: `${messages.checkedAt}: ${checkedAt} · ${getVersionLabel()}`,
+      },
+      // Context elements reject empty text
+      ...(footerText?.trim() ? [{ type: 'mrkdwn', text: footerText }] : []),
+    ],
+  };
+}
 
-  private formatStatus(status: string): string {
-    return status
-      .split('_')
-      .map((word) => word.charAt(0).toUpperCase() + word.slice(1).toLowerCase())
-      .join(' ');
+/**
+ * Format a slack-mentions entry: `subteam:ID` mentions a user group, `here`/`channel`
+ * notify the channel, and anything else is a user ID
+ */
+function formatMention(mention: string): string {
+  if (mention.startsWith('subteam:')) {
+    return `<!subteam^${mention.slice('subteam:'.length)}>`;
+  }
+  if (mention === 'here' || mention === 'channel') {
+    return `<!${mention}>`;
   }
+  return `<@${mention}>`;
 }
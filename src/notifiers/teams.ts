@@ -0,0 +1,130 @@
+import * as core from '@actions/core';
+import { NotificationPayload, Notifier, TeamsConfig } from '../types';
+import { getMessages, Language } from '../types/i18n';
+import { HttpRequester } from '../utils/http';
+import { redact } from '../utils/redact';
+import { formatCheckedAt, formatReleaseInfo, formatStatus, formatTimestamp, formatTitle, getStatusColor, getStatusEmoji, getStatusHexColor } from '../utils/status';
+
+export class TeamsNotifier implements Notifier {
+  private config: TeamsConfig;
+  private http: HttpRequester;
+  private language: Language;
+
+  constructor(config: TeamsConfig, http: HttpRequester) {
+    this.config = config;
+    this.http = http;
+    this.language = config.language || 'en';
+
+    if (!config.webhookUrl) {
+      throw new Error('webhookUrl must be provided for Microsoft Teams notifications');
+    }
+  }
+
+  async sendNotification(payload: NotificationPayload): Promise<void> {
+    const messages = getMessages(this.language);
+    const emoji = getStatusEmoji(payload.currentStatus);
+    const releaseInfo = formatReleaseInfo(payload, messages);
+
+    const facts = [
+      { name: messages.platform, value: payload.platform },
+      { name: messages.version, value: payload.version },
+      { name: messages.currentStatus, value: formatStatus(payload.currentStatus) },
+      ...(payload.previousStatus
+        ? [{ name: messages.previousStatus, value: formatStatus(payload.previousStatus) }]
+        : []),
+      ...(payload.rolloutPercentage !== undefined
+        ? [{ name: messages.rollout, value: `${payload.rolloutPercentage}%` }]
+        : []),
+      ...(releaseInfo ? [{ name: messages.release, value: releaseInfo }] : []),
+      ...(payload.submittedAt ? [{ name: messages.submitted, value: formatTimestamp(payload.submittedAt) }] : []),
+      ...(payload.daysInReview !== undefined ? [{ name: messages.daysInReview, value: `${payload.daysInReview}` }] : []),
+      ...(payload.appName ? [{ name: messages.appName, value: payload.appName }] : []),
+      ...(getStatusColor(payload.currentStatus) === 'danger'
+        ? [
+            {
+              name: messages.rejectionReason,
+              value: payload.rejectionReason || messages.rejectionReasonUnavailable(payload.platform),
+            },
+          ]
+        : []),
+    ];
+
+    const notes = [
+      ...(payload.actionRequired ? [`🚀 **${messages.actionRequiredRelease}**`] : []),
+      ...(payload.rolloutCompleted ? [`🎉 **${messages.rolloutCompleted}**`] : []),
+      ...(payload.nowLive
+        ? [`🎉 **${messages.nowLive}**${payload.storeUrl ? ` [${messages.viewListing}](${payload.storeUrl})` : ''}`]
+        : []),
+      ...(payload.credentialsInvalid ? [`🔑 **${messages.credentialsInvalid(payload.platform)}**`] : []),
+      ...(payload.reminderHours !== undefined ? [`⏰ ${messages.statusReminder(payload.reminderHours)}`] : []),
+      ...(payload.monitoringLapsedHours !== undefined
+        ? [`⚠️ ${messages.monitoringLapsed(payload.monitoringLapsedHours)}`]
+        : []),
+      ...(payload.flapping
+        ? [`🔁 ${messages.flappingDetected(payload.flapping.maxTransitions, payload.flapping.windowHours)}`]
+        : []),
+    ];
+
+    // Legacy MessageCard format, accepted by Teams incoming webhooks and Workflows
+    const card = {
+      '@type': 'MessageCard',
+      '@context': 'https://schema.org/extensions',
+      themeColor: getStatusHexColor(payload.currentStatus).replace('#', ''),
+      summary: messages.fallbackMessage(payload.platform, formatStatus(payload.currentStatus)),
+      sections: [
+        {
+          activityTitle: `${payload.regression ? `⚠️ ${messages.regression}: ` : ''}${emoji} ${formatTitle(payload, messages)}`,
+          activitySubtitle: `${messages.checkedAt}: ${formatCheckedAt(new Date())}`,
+          ...(notes.length > 0 ? { text: notes.join('\n\n') } : {}),
+          facts: facts,
+          markdown: true,
+        },
+      ],
+    };
+
+    if (this.config.dryRun) {
+      core.info(redact(`[dry-run] Microsoft Teams payload: ${JSON.stringify(card)}`));
+      return;
+    }
+
+    await this.http.request({
+      method: 'post',
+      url: this.config.webhookUrl,
+      data: card,
+      headers: {
+        'Content-Type': 'application/json',
+      },
+    });
+  }
+
+  async sendHeartbeat(checkedCount: number): Promise<void> {
+    const messages = getMessages(this.language);
+    const card = {
+      '@type': 'MessageCard',
+      '@context': 'https://schema.org/extensions',
+      themeColor: '2EB886',
+      summary: messages.heartbeat(checkedCount),
+      sections: [
+        {
+          activityTitle: `💚 ${messages.heartbeat(checkedCount)}`,
+          activitySubtitle: `${messages.checkedAt}: ${formatCheckedAt(new Date())}`,
+          markdown: true,
+        },
+      ],
+    };
+
+    if (this.config.dryRun) {
+      core.info(redact(`[dry-run] Microsoft Teams heartbeat payload: ${JSON.stringify(card)}`));
+      return;
+    }
+
+    await this.http.request({
+      method: 'post',
+      url: this.config.webhookUrl,
+      data: card,
+      headers: {
+        'Content-Type': 'application/json',
+      },
+    });
+  }
+}
@@ -0,0 +1,172 @@
+import * as core from '@actions/core';
+import axios, { AxiosResponse } from 'axios';
+import { NotificationPayload, Notifier, TelegramConfig } from '../types';
+import { getMessages, Language } from '../types/i18n';
+import { HttpRequester } from '../utils/http';
+import { redact } from '../utils/redact';
+import { formatCheckedAt, formatReleaseInfo, formatStatus, formatTimestamp, formatTitle, getStatusColor, getStatusEmoji } from '../utils/status';
+
+const TELEGRAM_API_URL = 'https://api.telegram.org';
+
+interface TelegramApiResponse {
+  ok: boolean;
+  description?: string;
+  result?: any;
+}
+
+/**
+ * Sends notifications through a Telegram bot with the Bot API's sendMessage
+ */
+export class TelegramNotifier implements Notifier {
+  private config: TelegramConfig;
+  private http: HttpRequester;
+  private language: Language;
+
+  constructor(config: TelegramConfig, http: HttpRequester) {
+    this.config = config;
+    this.http = http;
+    this.language = config.language || 'en';
+
+    if (!config.botToken || !config.chatId) {
+      throw new Error('botToken and chatId must be provided for Telegram notifications');
+    }
+  }
+
+  async validate(): Promise<void> {
+    const result = await this.callBotApi('getMe', {});
+    core.info(`Telegram bot token is valid (bot: @${result.result?.username})`);
+  }
+
+  async sendNotification(payload: NotificationPayload): Promise<void> {
+    const messages = getMessages(this.language);
+    const emoji = getStatusEmoji(payload.currentStatus);
+    const releaseInfo = formatReleaseInfo(payload, messages);
+
+    const regressionPrefix = payload.regression ? `⚠️ ${messages.regression}: ` : '';
+    const transition = payload.previousStatus
+      ? `${formatStatus(payload.previousStatus)} → ${formatStatus(payload.currentStatus)}`
+      : formatStatus(payload.currentStatus);
+
+    const lines = [
+      `${regressionPrefix}${escapeMarkdown(emoji)} *${escapeMarkdown(formatTitle(payload, messages))}*`,
+      ...(payload.actionRequired ? ['', `🚀 *${escapeMarkdown(messages.actionRequiredRelease)}*`] : []),
+      ...(payload.rolloutCompleted ? ['', `🎉 *${escapeMarkdown(messages.rolloutCompleted)}*`] : []),
+      ...(payload.nowLive
+        ? [
+            '',
+            `🎉 *${escapeMarkdown(messages.nowLive)}*${payload.storeUrl ? ` [${escapeMarkdown(messages.viewListing)}](${payload.storeUrl})` : ''}`,
+          ]
+        : []),
+      ...(payload.credentialsInvalid
+        ? ['', `🔑 *${escapeMarkdown(messages.credentialsInvalid(payload.platform))}*`]
+        : []),
+      '',
+      `*${escapeMarkdown(messages.platform)}:* ${escapeMarkdown(payload.platform)}`,
+      `*${escapeMarkdown(messages.version)}:* ${escapeMarkdown(payload.version)}`,
+      `*${escapeMarkdown(messages.currentStatus)}:* ${escapeMarkdown(transition)}`,
+      ...(payload.rolloutPercentage !== undefined
+        ? [`*${escapeMarkdown(messages.rollout)}:* ${payload.rolloutPercentage}%`]
+        : []),
+      ...(releaseInfo ? [`*${escapeMarkdown(messages.release)}:* ${escapeMarkdown(releaseInfo)}`] : []),
+      ...(payload.submittedAt
+        ? [`*${escapeMarkdown(messages.submitted)}:* ${escapeMarkdown(formatTimestamp(payload.submittedAt))}`]
+        : []),
+      ...(payload.daysInReview !== undefined
+        ? [`*${escapeMarkdown(messages.daysInReview)}:* ${payload.daysInReview}`]
+        : []),
+      ...(payload.appName ? [`*${escapeMarkdown(messages.appName)}:* ${escapeMarkdown(payload.appName)}`] : []),
+      ...(getStatusColor(payload.currentStatus) === 'danger'
+        ? [
+            `*${escapeMarkdown(messages.rejectionReason)}:* ${escapeMarkdown(
+              payload.rejectionReason || messages.rejectionReasonUnavailable(payload.platform)
+            )}`,
+          ]
+        : []),
+      ...(payload.reminderHours !== undefined
+        ? ['', `⏰ ${escapeMarkdown(messages.statusReminder(payload.reminderHours))}`]
+        : []),
+      ...(payload.monitoringLapsedHours !== undefined
+        ? ['', `⚠️ ${escapeMarkdown(messages.monitoringLapsed(payload.monitoringLapsedHours))}`]
+        : []),
+      ...(payload.flapping
+        ? [
+            '',
+            `🔁 ${escapeMarkdown(messages.flappingDetected(payload.flapping.maxTransitions, payload.flapping.windowHours))}`,
+          ]
+        : []),
+      '',
+      `_${escapeMarkdown(`${messages.checkedAt}: ${formatCheckedAt(new Date())}`)}_`,
+    ];
+
+    const body = {
+      chat_id: this.config.chatId,
+      text: lines.join('\n'),
+      parse_mode: 'Markdown',
+      disable_web_page_preview: true,
+    };
+
+    if (this.config.dryRun) {
+      core.info(redact(`[dry-run] Telegram sendMessage payload: ${JSON.stringify(body)}`));
+      return;
+    }
+
+    await this.callBotApi('sendMessage', body);
+  }
+
+  async sendHeartbeat(checkedCount: number): Promise<void> {
+    const messages = getMessages(this.language);
+    const body = {
+      chat_id: this.config.chatId,
+      text: [
+        `💚 ${escapeMarkdown(messages.heartbeat(checkedCount))}`,
+        `_${escapeMarkdown(`${messages.checkedAt}: ${formatCheckedAt(new Date())}`)}_`,
+      ].join('\n'),
+      parse_mode: 'Markdown',
+      disable_web_page_preview: true,
+      // Nothing needs attention, so don't ping anyone
+      disable_notification: true,
+    };
+
+    if (this.config.dryRun) {
+      core.info(redact(`[dry-run] Telegram sendMessage heartbeat payload: ${JSON.stringify(body)}`));
+      return;
+    }
+
+    await this.callBotApi('sendMessage', body);
+  }
+
+  private async callBotApi(method: string, body: object): Promise<TelegramApiResponse> {
+    let response: AxiosResponse<TelegramApiResponse>;
+    try {
+      response = await this.http.request<TelegramApiResponse>({
+        method: 'post',
+        url: `${TELEGRAM_API_URL}/bot${this.config.botToken}/${method}`,
+        data: body,
+        headers: {
+          'Content-Type': 'application/json',
+        },
+      });
+    } catch (error) {
+      // Errors come back as non-2xx responses whose ok:false body explains the problem
+      const description = axios.isAxiosError(error)
+        ? (error.response?.data as TelegramApiResponse | undefined)?.description
+        : undefined;
+      if (description) {
+        throw new Error(`Telegram ${method} failed: ${description}`);
+      }
+      throw error;
+    }
+
+    if (!response.data.ok) {
+      throw new Error(`Telegram ${method} failed: ${response.data.description || 'unknown error'}`);
+    }
+    return response.data;
+  }
+}
+
+/**
+ * Escape the characters that legacy Telegram Markdown treats as formatting
+ */
+function escapeMarkdown(value: string): string {
+  return value.replace(/([_*`[])/g, '\\$1');
+}
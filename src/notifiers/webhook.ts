@@ -0,0 +1,60 @@
+import * as core from '@actions/core';
+import { createHmac } from 'crypto';
+import { GenericWebhookConfig, NotificationPayload, Notifier } from '../types';
+import { HttpRequester } from '../utils/http';
+import { redact } from '../utils/redact';
+
+/**
+ * Posts review events as JSON with a stable schema, for internal services
+ */
+export class GenericWebhookNotifier implements Notifier {
+  private config: GenericWebhookConfig;
+  private http: HttpRequester;
+
+  constructor(config: GenericWebhookConfig, http: HttpRequester) {
+    this.config = config;
+    this.http = http;
+
+    if (!config.url) {
+      throw new Error('url must be provided for generic webhook notifications');
+    }
+  }
+
+  async sendNotification(payload: NotificationPayload): Promise<void> {
+    const event = payload.event;
+    const body = JSON.stringify({
+      platform: payload.platform,
+      appId: event?.appId ?? null,
+      packageName: event?.packageName ?? null,
+      version: event?.version ?? payload.version,
+      buildNumber: event?.buildNumber ?? null,
+      versionCode: event?.versionCode ?? null,
+      currentStatus: payload.currentStatus,
+      previousStatus: payload.previousStatus ?? null,
+      changed: event?.changed ?? true,
+      recovered: event?.recovered ?? false,
+      timestamp: new Date().toISOString(),
+    });
+
+    const headers: Record<string, string> = {
+      'Content-Type': 'application/json',
+    };
+
+    // Signed over the exact bytes sent so the receiver can verify them before parsing
+    if (this.config.secret) {
+      headers['X-Signature'] = `sha256=${createHmac('sha256', this.config.secret).update(body).digest('hex')}`;
+    }
+
+    if (this.config.dryRun) {
+      core.info(redact(`[dry-run] Generic webhook payload: ${body}`));
+      return;
+    }
+
+    await this.http.request({
+      method: 'post',
+      url: this.config.url,
+      data: body,
+      headers: headers,
+    });
+  }
+}
@@ -1,4 +1,4 @@
-export type Language = 'en' | 'ja';
+export type Language = 'en' | 'ja' | 'de' | 'fr' | 'es' | 'ko';
 
 export interface Messages {
   reviewStatusUpdate: string;
@@ -8,6 +8,32 @@ export interface Messages {
   previousStatus: string;
   appName: string;
   checkedAt: string;
+  rejectionReason: string;
+  regression: string;
+  release: string;
+  phasedRelease: string;
+  phasedReleaseDay: (day: number, total: number) => string;
+  rollout: string;
+  submitted: string;
+  daysInReview: string;
+  rejectionReasonUnavailable: (platform: string) => string;
+  rejectionReasonInThread: string;
+  rejectionReasonTruncated: string;
+  monitoringLapsed: (hours: number) => string;
+  credentialsInvalid: (platform: string) => string;
+  statusReminder: (hours: number) => string;
+  heartbeat: (checkedCount: number) => string;
+  flappingDetected: (transitions: number, hours: number) => string;
+  actionRequiredRelease: string;
+  rolloutCompleted: string;
+  nowLive: string;
+  viewListing: string;
+  openConsole: (console: string) => string;
+  acknowledge: string;
+  digestSummary: (count: number) => string;
+  unchangedCount: (count: number) => string;
+  failedCount: (count: number) => string;
+  quietHoursSummary: (count: number) => string;
   fallbackMessage: (platform: string, status: string) => string;
 }
 
@@ -19,6 +45,45 @@ const enMessages: Messages = {
   previousStatus: 'Previous Status',
   appName: 'App Name',
   checkedAt: 'Checked at',
+  rejectionReason: 'Rejection Reason',
+  regression: 'Regression',
+  release: 'Release',
+  phasedRelease: 'Phased release',
+  phasedReleaseDay: (day: number, total: number) =>
+    `Phased release day ${day} of ${total}`,
+  rollout: 'Rollout',
+  submitted: 'Submitted',
+  daysInReview: 'Days in review',
+  rejectionReasonUnavailable: (platform: string) =>
+    platform === 'Google Play'
+      ? 'See the Play Console for details'
+      : platform === 'Huawei AppGallery'
+        ? 'See AppGallery Connect for details'
+        : platform === 'App Store Build'
+          ? 'Apple emails the processing error to the account that uploaded the build'
+          : 'See the Resolution Center in App Store Connect for the reviewer\'s message',
+  rejectionReasonInThread: 'Shortened here; the full text is attached in the thread',
+  rejectionReasonTruncated: 'Shortened here; see the store console for the full text',
+  monitoringLapsed: (hours: number) =>
+    `Monitoring may have lapsed: the previous check was ${hours} hours ago`,
+  credentialsInvalid: (platform: string) =>
+    `Store monitoring credentials for ${platform} are invalid`,
+  statusReminder: (hours: number) =>
+    `Reminder: the status hasn't changed in ${hours} hours`,
+  heartbeat: (checkedCount: number) =>
+    `Monitoring active, no changes (${checkedCount} apps/tracks checked)`,
+  flappingDetected: (transitions: number, hours: number) =>
+    `Flapping detected: the status changed more than ${transitions} times in the last ${hours} hours`,
+  actionRequiredRelease: 'Action required: release this version in App Store Connect',
+  rolloutCompleted: 'Rollout completed: this release is now available to all users',
+  nowLive: 'Now live on the App Store!',
+  viewListing: 'View the App Store listing',
+  openConsole: (console: string) => `Open in ${console}`,
+  acknowledge: 'Acknowledge',
+  digestSummary: (count: number) => `${count} review status changes`,
+  unchangedCount: (count: number) => `${count} unchanged`,
+  failedCount: (count: number) => `${count} failed to check`,
+  quietHoursSummary: (count: number) => `${count} notifications held during quiet hours`,
   fallbackMessage: (platform: string, status: string) =>
     `${platform} review status changed to ${status}`,
 };
@@ -31,15 +96,266 @@ const jaMessages: Messages = {
   previousStatus: '前回のステータス',
   appName: 'アプリ名',
   checkedAt: '確認日時',
+  rejectionReason: '却下理由',
+  regression: 'ステータス後退',
+  release: 'リリース',
+  phasedRelease: '段階的リリース',
+  phasedReleaseDay: (day: number, total: number) =>
+    `段階的リリース ${day}/${total}日目`,
+  rollout: 'ロールアウト',
+  submitted: '提出日時',
+  daysInReview: '審査日数',
+  rejectionReasonUnavailable: (platform: string) =>
+    platform === 'Google Play'
+      ? '詳細はPlay Consoleで確認してください'
+      : platform === 'Huawei AppGallery'
+        ? '詳細はAppGallery Connectで確認してください'
+        : platform === 'App Store Build'
+          ? 'ビルド処理のエラー内容は、ビルドをアップロードしたアカウントにAppleからメールで届きます'
+          : 'レビュアーからのメッセージはApp Store Connectの解決センターで確認してください',
+  rejectionReasonInThread: 'ここでは省略しています。全文はスレッドに添付しています',
+  rejectionReasonTruncated: 'ここでは省略しています。全文はストアのコンソールで確認してください',
+  monitoringLapsed: (hours: number) =>
+    `監視が途切れていた可能性があります（前回の確認は${hours}時間前）`,
+  credentialsInvalid: (platform: string) =>
+    `${platform} のストア監視用の認証情報が無効です`,
+  statusReminder: (hours: number) =>
+    `リマインダー: ${hours} 時間ステータスが変わっていません`,
+  heartbeat: (checkedCount: number) =>
+    `監視中、変更はありません（${checkedCount} 件のアプリ/トラックを確認）`,
+  flappingDetected: (transitions: number, hours: number) =>
+    `ステータスの揺れを検出しました（過去${hours}時間で${transitions}回を超えて変化）`,
+  actionRequiredRelease: '対応が必要です: App Store Connectでこのバージョンをリリースしてください',
+  rolloutCompleted: 'ロールアウト完了: このリリースはすべてのユーザーに配信されています',
+  nowLive: 'App Store で公開されました！',
+  viewListing: 'App Store のページを見る',
+  openConsole: (console: string) => `${console}で開く`,
+  acknowledge: '確認済みにする',
+  digestSummary: (count: number) => `${count}件の審査ステータス変更`,
+  unchangedCount: (count: number) => `変更なし ${count}件`,
+  failedCount: (count: number) => `確認失敗 ${count}件`,
+  quietHoursSummary: (count: number) => `通知停止時間帯に保留された通知 ${count}件`,
   fallbackMessage: (platform: string, status: string) =>
     `${platform}の審査ステータスが${status}に変更されました`,
 };
 
+const deMessages: Messages = {
+  reviewStatusUpdate: 'Aktualisierung des Prüfstatus',
+  platform: 'Plattform',
+  version: 'Version',
+  currentStatus: 'Aktueller Status',
+  previousStatus: 'Vorheriger Status',
+  appName: 'App-Name',
+  checkedAt: 'Geprüft am',
+  rejectionReason: 'Ablehnungsgrund',
+  regression: 'Rückschritt',
+  release: 'Veröffentlichung',
+  phasedRelease: 'Phasenweise Veröffentlichung',
+  phasedReleaseDay: (day: number, total: number) =>
+    `Phasenweise Veröffentlichung: Tag ${day} von ${total}`,
+  rollout: 'Rollout',
+  submitted: 'Eingereicht',
+  daysInReview: 'Tage in Prüfung',
+  rejectionReasonUnavailable: (platform: string) =>
+    platform === 'Google Play'
+      ? 'Details findest du in der Play Console'
+      : platform === 'Huawei AppGallery'
+        ? 'Details findest du in AppGallery Connect'
+        : platform === 'App Store Build'
+          ? 'Apple sendet den Verarbeitungsfehler per E-Mail an das Konto, das den Build hochgeladen hat'
+          : 'Die Nachricht des Prüfers findest du im Resolution Center von App Store Connect',
+  rejectionReasonInThread: 'Hier gekürzt; der vollständige Text ist im Thread angehängt',
+  rejectionReasonTruncated: 'Hier gekürzt; den vollständigen Text finden Sie in der Store-Konsole',
+  monitoringLapsed: (hours: number) =>
+    `Die Überwachung war möglicherweise unterbrochen: Die letzte Prüfung war vor ${hours} Stunden`,
+  credentialsInvalid: (platform: string) =>
+    `Die Zugangsdaten der Store-Überwachung für ${platform} sind ungültig`,
+  statusReminder: (hours: number) =>
+    `Erinnerung: Der Status hat sich seit ${hours} Stunden nicht geändert`,
+  heartbeat: (checkedCount: number) =>
+    `Überwachung aktiv, keine Änderungen (${checkedCount} Apps/Tracks geprüft)`,
+  flappingDetected: (transitions: number, hours: number) =>
+    `Flattern erkannt: Der Status hat sich in den letzten ${hours} Stunden mehr als ${transitions}-mal geändert`,
+  actionRequiredRelease: 'Aktion erforderlich: Veröffentliche diese Version in App Store Connect',
+  rolloutCompleted: 'Rollout abgeschlossen: Diese Version ist jetzt für alle Nutzer verfügbar',
+  nowLive: 'Jetzt im App Store verfügbar!',
+  viewListing: 'Im App Store ansehen',
+  openConsole: (console: string) => `In ${console} öffnen`,
+  acknowledge: 'Bestätigen',
+  digestSummary: (count: number) => `${count} Änderungen des Prüfstatus`,
+  unchangedCount: (count: number) => `${count} unverändert`,
+  failedCount: (count: number) => `${count} nicht geprüft`,
+  quietHoursSummary: (count: number) => `${count} während der Ruhezeit zurückgehaltene Benachrichtigungen`,
+  fallbackMessage: (platform: string, status: string) =>
+    `Der ${platform}-Prüfstatus wurde zu ${status} geändert`,
+};
+
+const frMessages: Messages = {
+  reviewStatusUpdate: 'Mise à jour du statut de vérification',
+  platform: 'Plateforme',
+  version: 'Version',
+  currentStatus: 'Statut actuel',
+  previousStatus: 'Statut précédent',
+  appName: "Nom de l'app",
+  checkedAt: 'Vérifié le',
+  rejectionReason: 'Motif du refus',
+  regression: 'Régression',
+  release: 'Publication',
+  phasedRelease: 'Publication progressive',
+  phasedReleaseDay: (day: number, total: number) =>
+    `Publication progressive : jour ${day} sur ${total}`,
+  rollout: 'Déploiement',
+  submitted: 'Soumis le',
+  daysInReview: 'Jours en examen',
+  rejectionReasonUnavailable: (platform: string) =>
+    platform === 'Google Play'
+      ? 'Consultez la Play Console pour plus de détails'
+      : platform === 'Huawei AppGallery'
+        ? 'Consultez AppGallery Connect pour plus de détails'
+        : platform === 'App Store Build'
+          ? "Apple envoie l'erreur de traitement par e-mail au compte qui a téléversé le build"
+          : "Consultez le Resolution Center d'App Store Connect pour le message de l'examinateur",
+  rejectionReasonInThread: 'Texte abrégé ; le texte complet est joint dans le fil',
+  rejectionReasonTruncated: 'Texte abrégé ; consultez la console du store pour le texte complet',
+  monitoringLapsed: (hours: number) =>
+    `La surveillance a peut-être été interrompue : la vérification précédente date de ${hours} heures`,
+  credentialsInvalid: (platform: string) =>
+    `Les identifiants de surveillance du store pour ${platform} ne sont pas valides`,
+  statusReminder: (hours: number) =>
+    `Rappel : le statut n'a pas changé depuis ${hours} heures`,
+  heartbeat: (checkedCount: number) =>
+    `Surveillance active, aucun changement (${checkedCount} apps/pistes vérifiées)`,
+  flappingDetected: (transitions: number, hours: number) =>
+    `Instabilité détectée : le statut a changé plus de ${transitions} fois au cours des ${hours} dernières heures`,
+  actionRequiredRelease: 'Action requise : publiez cette version dans App Store Connect',
+  rolloutCompleted: 'Déploiement terminé : cette version est désormais disponible pour tous les utilisateurs',
+  nowLive: 'Maintenant disponible sur l\'App Store !',
+  viewListing: 'Voir la fiche App Store',
+  openConsole: (console: string) => `Ouvrir dans ${console}`,
+  acknowledge: 'Prendre en compte',
+  digestSummary: (count: number) => `${count} changements de statut de vérification`,
+  unchangedCount: (count: number) => `${count} inchangés`,
+  failedCount: (count: number) => `${count} non vérifiés`,
+  quietHoursSummary: (count: number) => `${count} notifications retenues pendant les heures calmes`,
+  fallbackMessage: (platform: string, status: string) =>
+    `Le statut de vérification ${platform} est passé à ${status}`,
+};
+
+const esMessages: Messages = {
+  reviewStatusUpdate: 'Actualización del estado de revisión',
+  platform: 'Plataforma',
+  version: 'Versión',
+  currentStatus: 'Estado actual',
+  previousStatus: 'Estado anterior',
+  appName: 'Nombre de la app',
+  checkedAt: 'Comprobado el',
+  rejectionReason: 'Motivo del rechazo',
+  regression: 'Regresión',
+  release: 'Lanzamiento',
+  phasedRelease: 'Lanzamiento gradual',
+  phasedReleaseDay: (day: number, total: number) =>
+    `Lanzamiento gradual: día ${day} de ${total}`,
+  rollout: 'Despliegue',
+  submitted: 'Enviado',
+  daysInReview: 'Días en revisión',
+  rejectionReasonUnavailable: (platform: string) =>
+    platform === 'Google Play'
+      ? 'Consulta Play Console para más detalles'
+      : platform === 'Huawei AppGallery'
+        ? 'Consulta AppGallery Connect para más detalles'
+        : platform === 'App Store Build'
+          ? 'Apple envía el error de procesamiento por correo a la cuenta que subió la compilación'
+          : 'Consulta el Resolution Center de App Store Connect para ver el mensaje del revisor',
+  rejectionReasonInThread: 'Texto abreviado; el texto completo está adjunto en el hilo',
+  rejectionReasonTruncated: 'Texto abreviado; consulta la consola de la tienda para ver el texto completo',
+  monitoringLapsed: (hours: number) =>
+    `Es posible que la supervisión se haya interrumpido: la comprobación anterior fue hace ${hours} horas`,
+  credentialsInvalid: (platform: string) =>
+    `Las credenciales de monitorización de la tienda para ${platform} no son válidas`,
+  statusReminder: (hours: number) =>
+    `Recordatorio: el estado no ha cambiado en ${hours} horas`,
+  heartbeat: (checkedCount: number) =>
+    `Monitorización activa, sin cambios (${checkedCount} apps/pistas comprobadas)`,
+  flappingDetected: (transitions: number, hours: number) =>
+    `Oscilación detectada: el estado cambió más de ${transitions} veces en las últimas ${hours} horas`,
+  actionRequiredRelease: 'Acción requerida: publica esta versión en App Store Connect',
+  rolloutCompleted: 'Despliegue completado: esta versión ya está disponible para todos los usuarios',
+  nowLive: '¡Ya disponible en el App Store!',
+  viewListing: 'Ver la ficha en el App Store',
+  openConsole: (console: string) => `Abrir en ${console}`,
+  acknowledge: 'Confirmar recepción',
+  digestSummary: (count: number) => `${count} cambios de estado de revisión`,
+  unchangedCount: (count: number) => `${count} sin cambios`,
+  failedCount: (count: number) => `${count} sin comprobar`,
+  quietHoursSummary: (count: number) => `${count} notificaciones retenidas durante las horas de silencio`,
+  fallbackMessage: (platform: string, status: string) =>
+    `El estado de revisión de ${platform} cambió a ${status}`,
+};
+
+const koMessages: Messages = {
+  reviewStatusUpdate: '심사 상태 업데이트',
+  platform: '플랫폼',
+  version: '버전',
+  currentStatus: '현재 상태',
+  previousStatus: '이전 상태',
+  appName: '앱 이름',
+  checkedAt: '확인 시각',
+  rejectionReason: '거절 사유',
+  regression: '상태 후퇴',
+  release: '출시',
+  phasedRelease: '단계적 출시',
+  phasedReleaseDay: (day: number, total: number) =>
+    `단계적 출시 ${total}일 중 ${day}일차`,
+  rollout: '출시 비율',
+  submitted: '제출 일시',
+  daysInReview: '심사 일수',
+  rejectionReasonUnavailable: (platform: string) =>
+    platform === 'Google Play'
+      ? '자세한 내용은 Play Console에서 확인하세요'
+      : platform === 'Huawei AppGallery'
+        ? '자세한 내용은 AppGallery Connect에서 확인하세요'
+        : platform === 'App Store Build'
+          ? '빌드 처리 오류는 빌드를 업로드한 계정으로 Apple이 이메일로 보냅니다'
+          : '심사자 메시지는 App Store Connect의 해결 센터에서 확인하세요',
+  rejectionReasonInThread: '일부만 표시됩니다. 전체 내용은 스레드에 첨부되어 있습니다',
+  rejectionReasonTruncated: '일부만 표시됩니다. 전체 내용은 스토어 콘솔에서 확인하세요',
+  monitoringLapsed: (hours: number) =>
+    `모니터링이 중단되었을 수 있습니다: 이전 확인은 ${hours}시간 전이었습니다`,
+  credentialsInvalid: (platform: string) =>
+    `${platform} 스토어 모니터링 자격 증명이 유효하지 않습니다`,
+  statusReminder: (hours: number) =>
+    `알림: ${hours}시간 동안 상태가 변경되지 않았습니다`,
+  heartbeat: (checkedCount: number) =>
+    `모니터링 중, 변경 없음 (앱/트랙 ${checkedCount}개 확인)`,
+  flappingDetected: (transitions: number, hours: number) =>
+    `상태 변동 감지: 지난 ${hours}시간 동안 상태가 ${transitions}회 넘게 바뀌었습니다`,
+  actionRequiredRelease: '조치 필요: App Store Connect에서 이 버전을 출시하세요',
+  rolloutCompleted: '출시 완료: 이 버전이 이제 모든 사용자에게 제공됩니다',
+  nowLive: '이제 App Store에 출시되었습니다!',
+  viewListing: 'App Store 페이지 보기',
+  openConsole: (console: string) => `${console}에서 열기`,
+  acknowledge: '확인',
+  digestSummary: (count: number) => `심사 상태 변경 ${count}건`,
+  unchangedCount: (count: number) => `변경 없음 ${count}건`,
+  failedCount: (count: number) => `확인 실패 ${count}건`,
+  quietHoursSummary: (count: number) => `방해 금지 시간 동안 보류된 알림 ${count}건`,
+  fallbackMessage: (platform: string, status: string) =>
+    `${platform} 심사 상태가 ${status}(으)로 변경되었습니다`,
+};
+
 export const messages: Record<Language, Messages> = {
   en: enMessages,
   ja: jaMessages,
+  de: deMessages,
+  fr: frMessages,
+  es: esMessages,
+  ko: koMessages,
 };
 
+export function isSupportedLanguage(language: string): language is Language {
+  return Object.prototype.hasOwnProperty.call(messages, language);
+}
+
 export function getMessages(language: Language): Messages {
   return messages[language] || messages.en;
 }
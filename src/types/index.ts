@@ -1,27 +1,280 @@
-export interface AppStoreConfig {
-  issuerId: string;
+import { ApiErrorKind } from '../utils/apiError';
+import { LogFormat } from '../utils/logger';
+import { Language } from './i18n';
+
+export interface AppStoreApiKey {
   keyId: string;
+  // base64, raw .p8 content or a path to the .p8 file
   privateKey: string;
-  appId: string;
+}
+
+export interface AppStoreConfig {
+  issuerId: string;
+  // Tried in order until one authenticates, so a key can be rotated without a monitoring gap
+  keys: AppStoreApiKey[];
+  // Numeric Apple IDs, not bundle IDs
+  appIds: string[];
+  // Resolved to app IDs through the API at the start of a run
+  bundleIds: string[];
+  // App Store platforms to monitor for each app (IOS, MAC_OS, TV_OS, VISION_OS)
+  platforms: string[];
+  // Also monitor TestFlight beta review of the latest build
+  monitorTestFlight: boolean;
+  // Also monitor the processing state of the latest uploaded build
+  monitorBuildProcessing: boolean;
+  // Also monitor the state of the latest review submission
+  monitorReviewSubmissions: boolean;
+  // Also monitor the review state of in-app purchases and subscriptions
+  monitorInAppPurchases: boolean;
+  // Version states in order of preference when several versions are in flight (uppercase)
+  versionStatePriority: string[];
+  // Regular expression a version string must match to be considered
+  versionFilter?: string;
+  // Replaces https://api.appstoreconnect.apple.com, e.g. with a mock server in tests
+  apiBaseUrl?: string;
 }
 
 export interface GooglePlayConfig {
   packageName: string;
   serviceAccount: string;
+  tracks: string[];
+  // Replace https://androidpublisher.googleapis.com and the OAuth token endpoint, e.g. with a mock server in tests
+  apiBaseUrl?: string;
+  tokenUrl?: string;
+}
+
+export interface AmazonConfig {
+  // Security profile credentials with App Submission API access
+  clientId: string;
+  clientSecret: string;
+  // e.g. amzn1.devportal.mobileapp.xxxxxxxx
+  appId: string;
+}
+
+export interface HuaweiConfig {
+  // AppGallery Connect API client (Users and permissions > API key > Connect API)
+  clientId: string;
+  clientSecret: string;
+  appId: string;
 }
 
 export interface SlackConfig {
   webhookUrl?: string;
   botToken?: string;
   channel?: string;
-  language?: 'en' | 'ja';
+  // Channel for rejection and regression notifications (bot token only), defaults to channel
+  rejectedChannel?: string;
+  language?: Language;
   mentions?: string[];
+  // Only mention for danger statuses and regressions
+  mentionsOnRejectionOnly?: boolean;
+  // Go text/template style message replacing the default blocks
+  template?: string;
+  // Go text/template style text added to the footer of the default layout
+  footerText?: string;
+  // Reply to the version's existing thread instead of posting a new message (bot token only)
+  threadByVersion?: boolean;
+  // Edit the version's first message with chat.update instead of posting a new one (bot token only)
+  updateInPlace?: boolean;
+  // Add a button linking to the store console
+  includeConsoleLinks?: boolean;
+  // Add interactive Acknowledge/Open Console buttons handled by a Slack app (bot token only)
+  enableActions?: boolean;
+  // Rejection reasons longer than this are shortened, with the full text uploaded as a snippet (bot token only)
+  snippetThreshold: number;
+  // Send all of a run's notifications as one message instead of one per app/track
+  consolidateNotifications?: boolean;
+  // Add the run's status counts to consolidated messages
+  statusCounts?: boolean;
+  dryRun?: boolean;
+  validateOnStart?: boolean;
+}
+
+export interface TeamsConfig {
+  webhookUrl: string;
+  language?: Language;
+  dryRun?: boolean;
+}
+
+export interface TelegramConfig {
+  botToken: string;
+  chatId: string;
+  language?: Language;
+  dryRun?: boolean;
+}
+
+export interface EmailConfig {
+  host: string;
+  port: number;
+  username: string;
+  password: string;
+  from: string;
+  to: string[];
+  language?: Language;
+  dryRun?: boolean;
+}
+
+export interface GenericWebhookConfig {
+  url: string;
+  // HMAC-SHA256 key for the X-Signature header
+  secret?: string;
+  dryRun?: boolean;
+}
+
+export interface PagerDutyConfig {
+  routingKey: string;
+  dryRun?: boolean;
+}
+
+export type OpsGenieRegion = 'us' | 'eu';
+
+export interface OpsGenieConfig {
+  apiKey: string;
+  region: OpsGenieRegion;
+  dryRun?: boolean;
+}
+
+// Rejection of an app/track raised to PagerDuty and OpsGenie
+export interface Incident {
+  platform: string;
+  // App ID or package name, so apps sharing a version number don't share an incident
+  subject: string;
+  version: string;
+  status: string;
+  previousStatus?: string;
+}
+
+export interface MetricsConfig {
+  // Prometheus pushgateway base URL, e.g. http://pushgateway:9091
+  pushgatewayUrl: string;
+  // cache-key, added to the grouping key so monitors with separate caches don't replace each other's series
+  cacheKey?: string;
+  dryRun?: boolean;
+}
+
+export interface HttpConfig {
+  maxRetries: number;
+  timeoutSeconds: number;
+  proxyUrl?: string;
+  userAgent: string;
+  // Random per-run ID sent as X-Correlation-ID and included in JSON logs
+  correlationId: string;
+}
+
+export type CacheUploadMethod = 'put' | 'post';
+
+export interface CacheConfig {
+  // Read the previous cache from this HTTP(S) URL instead of the workflow artifact
+  url?: string;
+  // Write the cache here instead of the workflow artifact, defaults to url
+  uploadUrl?: string;
+  uploadMethod: CacheUploadMethod;
+  // Read and write the cache as a file of this GitHub Gist instead of the workflow artifact
+  gist?: GistCacheConfig;
+  // Suffix of the artifact name and gist file, so several monitors can keep separate caches
+  key?: string;
+  historyLimit: number;
+  // Warn when the previous cache is older than this (0 disables the check)
+  maxAgeHours: number;
+  // Replace the default statuses used for recovery/regression detection when set (lowercase)
+  rejectedStatuses?: string[];
+  approvedStatuses?: string[];
+  // Flag statuses that keep changing within the cached history (unset disables the check)
+  flapping?: FlappingConfig;
+}
+
+export interface GistCacheConfig {
+  gistId: string;
+  // Needs the gist scope; the workflow's GITHUB_TOKEN can't access gists
+  token: string;
+}
+
+export interface FlappingConfig {
+  windowHours: number;
+  // Flapping when the status changed more than this many times within the window
+  maxTransitions: number;
+}
+
+export interface NotificationConfig {
+  notifyOnInReview: boolean;
+  // Replaces the default notify statuses when set (lowercase)
+  notifyStatuses?: string[];
+  // Only notify status changes into these states, replacing every other notify condition (lowercase)
+  notifyOnTransitionTo?: string[];
+  // Notify when a Google Play staged rollout percentage changes
+  notifyOnRolloutChange: boolean;
+  // Notify every version/build change, even in statuses outside the notify list
+  notifyOnAnyVersionChange: boolean;
+  // Suppress re-notifying an identical status within this many minutes (0 disables)
+  cooldownMinutes: number;
+  // Re-notify an unchanged non-critical status after this many hours (0 disables)
+  statusReminderIntervalHours: number;
+  // Post a "no changes" message on quiet runs this many hours apart (0 disables)
+  heartbeatIntervalHours: number;
+  // Notify the current statuses when there is no previous cache
+  notifyOnFirstRun: boolean;
+  // Alert once when a store rejects the credentials, until its checks pass again
+  alertOnAuthFailure: boolean;
+  quietHours?: QuietHoursConfig;
+  // Lowercase status substring -> emoji, consulted before the default emoji
+  statusEmojiMap: Record<string, string>;
+  // IANA time zone for timestamps shown in notifications
+  displayTimeZone: string;
+}
+
+export interface QuietHoursConfig {
+  // Minutes after midnight in timeZone; the window wraps past midnight when start > end
+  startMinutes: number;
+  endMinutes: number;
+  timeZone: string;
+  // Send rejections and regressions during quiet hours
+  allowCritical: boolean;
+}
+
+export type RunMode = 'monitor' | 'doctor' | 'watch' | 'list-apps' | 'list-play-tracks';
+
+export interface WatchConfig {
+  // Pause between the end of one check and the start of the next
+  intervalSeconds: number;
+  // Save the in-memory cache at most this often (0 saves after every check)
+  saveIntervalSeconds: number;
+}
+
+// A store left unmonitored because inputs it needs are empty
+export interface SkippedPlatform {
+  // app-store, google-play, amazon or huawei
+  platform: string;
+  // e.g. "missing app-store-key-id"
+  reason: string;
 }
 
 export interface MonitorConfig {
+  http: HttpConfig;
+  cache: CacheConfig;
+  notifications: NotificationConfig;
   appStore?: AppStoreConfig;
   googlePlay?: GooglePlayConfig;
-  slack: SlackConfig;
+  amazon?: AmazonConfig;
+  huawei?: HuaweiConfig;
+  // Stores without the configuration they need, in the order they're checked
+  skippedPlatforms: SkippedPlatform[];
+  slack?: SlackConfig;
+  teams?: TeamsConfig;
+  genericWebhook?: GenericWebhookConfig;
+  email?: EmailConfig;
+  telegram?: TelegramConfig;
+  pagerDuty?: PagerDutyConfig;
+  opsGenie?: OpsGenieConfig;
+  metrics?: MetricsConfig;
+  logFormat: LogFormat;
+  mode: RunMode;
+  // Set in watch mode
+  watch?: WatchConfig;
+  // Fail the step instead of only warning
+  failOnNotificationError: boolean;
+  failOnApiError: boolean;
+  // App Store requests in flight at once while checking several apps and platforms
+  maxConcurrency: number;
 }
 
 export enum AppStoreReviewStatus {
@@ -39,6 +292,32 @@ export enum AppStoreReviewStatus {
   INVALID_BINARY = 'INVALID_BINARY',
 }
 
+export enum TestFlightReviewStatus {
+  WAITING_FOR_BETA_REVIEW = 'WAITING_FOR_BETA_REVIEW',
+  IN_BETA_REVIEW = 'IN_BETA_REVIEW',
+  APPROVED = 'APPROVED',
+  REJECTED = 'REJECTED',
+}
+
+// Processing happens between upload and review, independently of appStoreState
+export enum BuildProcessingState {
+  PROCESSING = 'PROCESSING',
+  FAILED = 'FAILED',
+  INVALID = 'INVALID',
+  VALID = 'VALID',
+}
+
+// State of a review submission as a whole, covering the version and any other items in it
+export enum ReviewSubmissionState {
+  READY_FOR_REVIEW = 'READY_FOR_REVIEW',
+  WAITING_FOR_REVIEW = 'WAITING_FOR_REVIEW',
+  IN_REVIEW = 'IN_REVIEW',
+  UNRESOLVED_ISSUES = 'UNRESOLVED_ISSUES',
+  CANCELING = 'CANCELING',
+  COMPLETING = 'COMPLETING',
+  COMPLETE = 'COMPLETE',
+}
+
 export enum GooglePlayReviewStatus {
   DRAFT = 'draft',
   IN_PROGRESS = 'inProgress',
@@ -46,20 +325,139 @@ export enum GooglePlayReviewStatus {
   COMPLETED = 'completed',
 }
 
+export enum AmazonReviewStatus {
+  // Open edit that hasn't been submitted yet
+  IN_PROGRESS = 'IN_PROGRESS',
+  SUBMITTED = 'SUBMITTED',
+  // No open edit, so the last submitted edit was published
+  LIVE = 'LIVE',
+}
+
+export interface PhasedReleaseInfo {
+  // INACTIVE, ACTIVE, PAUSED or COMPLETE
+  state: string;
+  currentDay?: number;
+}
+
 export interface AppStoreReviewInfo {
   appId: string;
+  appName?: string;
+  platform: string;
   version: string;
   buildNumber?: string;
   status: AppStoreReviewStatus;
   statusChangedAt?: Date;
+  rejectionReason?: string;
+  // MANUAL, AFTER_APPROVAL or SCHEDULED
+  releaseType?: string;
+  phasedRelease?: PhasedReleaseInfo;
+  // ISO 8601 timestamps: version creation, scheduled release and submission for review
+  createdDate?: string;
+  earliestReleaseDate?: string;
+  submittedDate?: string;
+}
+
+export interface TestFlightReviewInfo {
+  appId: string;
+  platform: string;
+  version: string;
+  buildNumber: string;
+  status: TestFlightReviewStatus;
+}
+
+export interface BuildProcessingInfo {
+  appId: string;
+  platform: string;
+  version: string;
+  buildNumber: string;
+  processingState: BuildProcessingState;
+}
+
+export interface ReviewSubmissionInfo {
+  appId: string;
+  platform: string;
+  submissionId: string;
+  // Version string of the App Store version in the submission, unset when it only has other items
+  version?: string;
+  state: ReviewSubmissionState;
+  submittedDate: string;
+}
+
+export type InAppPurchaseKind = 'In-App Purchase' | 'Subscription';
+
+// In-app purchases and subscriptions are reviewed separately from the app binary
+export interface InAppPurchaseInfo {
+  appId: string;
+  productId: string;
+  name: string;
+  kind: InAppPurchaseKind;
+  // e.g. WAITING_FOR_REVIEW, APPROVED, REJECTED, DEVELOPER_ACTION_NEEDED
+  state: string;
+}
+
+// An app visible to the App Store Connect API key, listed by mode: list-apps
+export interface AppStoreAppInfo {
+  appId: string;
+  name: string;
+  bundleId: string;
+}
+
+// A track of the Google Play package, listed by mode: list-play-tracks
+export interface GooglePlayTrackInfo {
+  track: string;
+  releaseCount: number;
+  // Name and status of the track's latest release
+  latestRelease?: { name?: string; status: string };
 }
 
 export interface GooglePlayReviewInfo {
   packageName: string;
+  // Store listing title in the app's default language
+  appName?: string;
+  track: string;
   versionCode: number;
   versionName?: string;
   status: GooglePlayReviewStatus;
   statusChangedAt?: Date;
+  // Staged rollout fraction (0-1), set while a rollout is in progress or after it was halted
+  userFraction?: number;
+  // Policy or review detail for a halted release. The Android Publisher API doesn't return
+  // policy violations, so this stays unset until it does and notifications point to the Play Console.
+  rejectionReason?: string;
+}
+
+export enum HuaweiReviewStatus {
+  RELEASED = 'RELEASED',
+  RELEASE_REJECTED = 'RELEASE_REJECTED',
+  // Removed by Huawei, including forcible removal
+  REMOVED_FROM_SALE = 'REMOVED_FROM_SALE',
+  RELEASING = 'RELEASING',
+  IN_REVIEW = 'IN_REVIEW',
+  UPDATING = 'UPDATING',
+  REMOVAL_REQUESTED = 'REMOVAL_REQUESTED',
+  DRAFT = 'DRAFT',
+  UPDATE_REJECTED = 'UPDATE_REJECTED',
+  RELEASE_CANCELED = 'RELEASE_CANCELED',
+}
+
+export interface AmazonReviewInfo {
+  appId: string;
+  // Unset once the edit has been published
+  editId?: string;
+  // APK version code, or the edit ID for edits without an APK
+  version: string;
+  status: AmazonReviewStatus;
+}
+
+export interface HuaweiReviewInfo {
+  appId: string;
+  // App name in the app's default language
+  appName?: string;
+  version: string;
+  // A HuaweiReviewStatus, or RELEASE_STATE_<code> for release states without a name
+  status: string;
+  // Reviewer's opinion of the latest audit
+  rejectionReason?: string;
 }
 
 export interface ReviewStatus {
@@ -69,10 +467,175 @@ export interface ReviewStatus {
 }
 
 export interface NotificationPayload {
-  platform: 'App Store' | 'Google Play';
+  platform: 'App Store' | 'App Store Build' | 'App Store Submission' | 'TestFlight' | 'Google Play' | 'Amazon Appstore' | 'Huawei AppGallery' | InAppPurchaseKind;
   appName?: string;
   version: string;
   previousStatus?: string;
   currentStatus: string;
   statusChangedAt?: Date;
+  rejectionReason?: string;
+  regression?: boolean;
+  releaseType?: string;
+  phasedRelease?: PhasedReleaseInfo;
+  // Release date of a SCHEDULED release (ISO 8601)
+  earliestReleaseDate?: string;
+  // When the version was submitted for review (ISO 8601)
+  submittedAt?: string;
+  // Whole days since submission, while in review or just decided
+  daysInReview?: number;
+  // Google Play staged rollout percentage (0-100) while in progress
+  rolloutPercentage?: number;
+  // Set when the previous check is older than cache-max-age-hours
+  monitoringLapsedHours?: number;
+  // Set when the status has been flapping within the configured window
+  flapping?: FlappingConfig;
+  // Set for the alert that the store rejected the monitor's own credentials
+  credentialsInvalid?: boolean;
+  // Set for a reminder of an unchanged status, with the hours since it was last notified
+  reminderHours?: number;
+  // Set for statuses that need a manual step, such as releasing a PENDING_DEVELOPER_RELEASE version
+  actionRequired?: boolean;
+  // Set when a Google Play staged rollout went from inProgress to completed
+  rolloutCompleted?: boolean;
+  // Set the first time an App Store version reaches READY_FOR_SALE
+  nowLive?: boolean;
+  // Public store listing, linked from the now-live message
+  storeUrl?: string;
+  // Store console page where the reviewer's message can be read and acted on
+  consoleUrl?: string;
+  // Slack thread to reply in or message to update, from a previous notification for the same version
+  slackThreadTs?: string;
+  // Idempotency key of the last message posted to Slack for the same app/track
+  slackLastMessageKey?: string;
+  // Unformatted details for machine consumers
+  event?: ReviewEvent;
+}
+
+export interface ReviewEvent {
+  appId?: string;
+  packageName?: string;
+  version: string;
+  buildNumber?: string;
+  versionCode?: number;
+  changed: boolean;
+  recovered: boolean;
+}
+
+// Channel-specific identifiers of a sent notification, kept for follow-up messages
+export interface NotificationReceipt {
+  slackThreadTs?: string;
+  // Set when the Slack bot posted the notification, or skipped it as already posted
+  slackMessageKey?: string;
+}
+
+export interface Notifier {
+  sendNotification(payload: NotificationPayload): Promise<NotificationReceipt | void>;
+  // Verify the channel is reachable and its credentials are valid
+  validate?(): Promise<void>;
+  // Send several notifications as a single message
+  sendDigest?(payloads: NotificationPayload[], heldDuringQuietHours?: boolean, statusCounts?: StatusCounts): Promise<void>;
+  // Confirm monitoring ran and found no changes
+  sendHeartbeat?(checkedCount: number): Promise<void>;
+}
+
+export interface AppStoreSummaryEntry {
+  appId: string;
+  platform?: string;
+  version?: string;
+  buildNumber?: string;
+  status?: string;
+  changed: boolean;
+  notified: boolean;
+  error?: string;
+  errorKind?: ApiErrorKind;
+}
+
+export interface InAppPurchaseSummaryEntry {
+  appId: string;
+  productId?: string;
+  kind?: InAppPurchaseKind;
+  status?: string;
+  changed: boolean;
+  notified: boolean;
+  error?: string;
+  errorKind?: ApiErrorKind;
+}
+
+export interface GooglePlaySummaryEntry {
+  track: string;
+  packageName: string;
+  versionCode?: number;
+  status?: string;
+  changed: boolean;
+  notified: boolean;
+  error?: string;
+  errorKind?: ApiErrorKind;
+}
+
+export interface AmazonSummaryEntry {
+  appId: string;
+  version?: string;
+  status?: string;
+  changed: boolean;
+  notified: boolean;
+  error?: string;
+  errorKind?: ApiErrorKind;
+}
+
+export interface HuaweiSummaryEntry {
+  appId: string;
+  version?: string;
+  status?: string;
+  changed: boolean;
+  notified: boolean;
+  error?: string;
+  errorKind?: ApiErrorKind;
+}
+
+// Number of apps/tracks checked in a run, by status
+export interface StatusCounts {
+  // Status -> count, for the apps/tracks whose version or status changed
+  changed: Record<string, number>;
+  unchanged: number;
+  failed: number;
+}
+
+// Machine-readable result of a run, exported as the summary-json output
+export interface RunSummary {
+  checkedAt: string;
+  notificationSent: boolean;
+  appStore: {
+    skipped: boolean;
+    apps: AppStoreSummaryEntry[];
+  };
+  testFlight: {
+    skipped: boolean;
+    apps: AppStoreSummaryEntry[];
+  };
+  // status holds the build's processingState
+  buildProcessing: {
+    skipped: boolean;
+    apps: AppStoreSummaryEntry[];
+  };
+  inAppPurchases: {
+    skipped: boolean;
+    products: InAppPurchaseSummaryEntry[];
+  };
+  // status holds the submission's state
+  reviewSubmissions: {
+    skipped: boolean;
+    apps: AppStoreSummaryEntry[];
+  };
+  googlePlay: {
+    skipped: boolean;
+    tracks: GooglePlaySummaryEntry[];
+  };
+  amazon: {
+    skipped: boolean;
+    apps: AmazonSummaryEntry[];
+  };
+  huawei: {
+    skipped: boolean;
+    apps: HuaweiSummaryEntry[];
+  };
 }
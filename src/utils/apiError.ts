@@ -0,0 +1,58 @@
+import axios from 'axios';
+
+/**
+ * Category of a failed store API call: rejected credentials, an exhausted rate limit or quota,
+ * a missing app or resource, or an outage worth trying again on the next run
+ */
+export type ApiErrorKind = 'auth' | 'rate_limited' | 'not_found' | 'transient' | 'other';
+
+/**
+ * Store API failure (after HttpClient has exhausted its retries), classified by kind so
+ * callers can branch on it without parsing the message
+ */
+export class StoreApiError extends Error {
+  readonly kind: ApiErrorKind;
+  readonly status?: number;
+
+  constructor(message: string, kind: ApiErrorKind, status?: number) {
+    super(message);
+    this.name = 'StoreApiError';
+    this.kind = kind;
+    this.status = status;
+  }
+}
+
+/**
+ * Kind of an HTTP failure by its status code. Requests without a response (timeouts, network
+ * errors) are transient. 403 is left to each store, since it means rejected credentials for
+ * some APIs and a missing permission for one resource for others.
+ */
+export function getHttpErrorKind(status: number | undefined): ApiErrorKind {
+  if (status === undefined || status === 408 || status >= 500) {
+    return 'transient';
+  }
+  if (status === 401) {
+    return 'auth';
+  }
+  if (status === 404) {
+    return 'not_found';
+  }
+  if (status === 429) {
+    return 'rate_limited';
+  }
+  return 'other';
+}
+
+/**
+ * Kind of an error thrown by a monitor. StoreApiErrors carry their own, and other HTTP errors
+ * are classified by status code.
+ */
+export function getApiErrorKind(error: unknown): ApiErrorKind {
+  if (error instanceof StoreApiError) {
+    return error.kind;
+  }
+  if (axios.isAxiosError(error)) {
+    return getHttpErrorKind(error.response?.status);
+  }
+  return 'other';
+}
@@ -0,0 +1,54 @@
+export type Limiter = <T>(task: () => Promise<T>) => Promise<T>;
+
+/**
+ * Result of a request started ahead of time, with how long the request itself took
+ */
+export interface Prefetched<T> {
+  value: T;
+  durationMs: number;
+}
+
+/**
+ * Create a limiter that runs at most maxConcurrency tasks at once, starting queued tasks
+ * in the order they were scheduled
+ */
+export function createLimiter(maxConcurrency: number): Limiter {
+  let active = 0;
+  const queue: (() => void)[] = [];
+
+  const startNext = () => {
+    if (active < maxConcurrency && queue.length > 0) {
+      active++;
+      queue.shift()!();
+    }
+  };
+
+  return <T>(task: () => Promise<T>) =>
+    new Promise<T>((resolve, reject) => {
+      queue.push(() => {
+        Promise.resolve()
+          .then(task)
+          .then(resolve, reject)
+          .finally(() => {
+            active--;
+            startNext();
+          });
+      });
+      startNext();
+    });
+}
+
+/**
+ * Schedule a request on the limiter, timing it from when it starts rather than from when it
+ * was queued. A failure is raised where the result is awaited, not as an unhandled rejection
+ * while earlier results are still being processed.
+ */
+export function prefetch<T>(limiter: Limiter, fetch: () => Promise<T>): Promise<Prefetched<T>> {
+  const result = limiter(async () => {
+    const startedAt = Date.now();
+    const value = await fetch();
+    return { value, durationMs: Date.now() - startedAt };
+  });
+  result.catch(() => undefined);
+  return result;
+}
@@ -0,0 +1,42 @@
+import * as fs from 'fs';
+import * as path from 'path';
+import { parse as parseYaml } from 'yaml';
+
+/**
+ * Read a YAML or JSON config file whose keys are action input names, e.g.
+ * `app-store-app-id: "123"` (snake_case keys are accepted too).
+ * Lists become comma-separated values so they read like the equivalent input.
+ */
+export function loadConfigFile(filePath: string): Record<string, string> {
+  if (!fs.existsSync(filePath)) {
+    throw new Error(`config-file ${filePath} does not exist`);
+  }
+
+  const content = fs.readFileSync(filePath, 'utf-8');
+  let parsed: unknown;
+  try {
+    // JSON is valid YAML, but JSON.parse gives clearer errors for .json files
+    parsed = path.extname(filePath).toLowerCase() === '.json' ? JSON.parse(content) : parseYaml(content);
+  } catch (error) {
+    throw new Error(`Failed to parse config-file ${filePath}: ${error instanceof Error ? error.message : error}`);
+  }
+
+  if (parsed === null || parsed === undefined) {
+    return {};
+  }
+  if (typeof parsed !== 'object' || Array.isArray(parsed)) {
+    throw new Error(`config-file ${filePath} must contain a mapping of input names to values`);
+  }
+
+  const values: Record<string, string> = {};
+  for (const [key, value] of Object.entries(parsed as Record<string, unknown>)) {
+    if (value === null || value === undefined) {
+      continue;
+    }
+    if (typeof value === 'object' && !Array.isArray(value)) {
+      throw new Error(`config-file key "${key}" must be a string, number, boolean or list`);
+    }
+    values[key.replace(/_/g, '-')] = Array.isArray(value) ? value.join(',') : `${value}`;
+  }
+  return values;
+}
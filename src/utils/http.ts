@@ -0,0 +1,146 @@
+import * as core from '@actions/core';
+import axios, { AxiosInstance, AxiosRequestConfig, AxiosResponse } from 'axios';
+import { Agent } from 'http';
+import { HttpsProxyAgent } from 'https-proxy-agent';
+import { HttpConfig } from '../types';
+import { redact } from './redact';
+
+const RETRYABLE_STATUS_CODES = [429, 500, 502, 503, 504];
+const BASE_DELAY_MS = 1000;
+const MAX_DELAY_MS = 30000;
+
+/**
+ * Performs outbound HTTP requests. Monitors and notifiers depend on this interface
+ * rather than a concrete client, so tests can inject a fake implementation.
+ */
+export interface HttpRequester {
+  request<T = any>(config: AxiosRequestConfig): Promise<AxiosResponse<T>>;
+}
+
+/**
+ * Shared HTTP client used for every outbound API call, so timeout and retry
+ * settings apply consistently
+ */
+export class HttpClient implements HttpRequester {
+  private instance: AxiosInstance;
+  private maxRetries: number;
+
+  constructor(config: HttpConfig) {
+    this.maxRetries = config.maxRetries;
+
+    let agent: Agent | undefined;
+    if (config.proxyUrl) {
+      core.info(`Using HTTP proxy: ${maskUrlCredentials(config.proxyUrl)}`);
+      agent = new HttpsProxyAgent(config.proxyUrl);
+    }
+
+    this.instance = axios.create({
+      timeout: config.timeoutSeconds * 1000,
+      headers: {
+        'User-Agent': config.userAgent,
+        'X-Correlation-ID': config.correlationId,
+      },
+      // Route through the agent instead of axios' own proxy handling, which doesn't tunnel HTTPS
+      ...(agent ? { httpsAgent: agent, proxy: false as const } : {}),
+    });
+  }
+
+  request<T = any>(config: AxiosRequestConfig): Promise<AxiosResponse<T>> {
+    return requestWithRetry<T>(this.instance, config, this.maxRetries);
+  }
+}
+
+/**
+ * Perform a request, retrying transient failures with exponential backoff and jitter.
+ * Honors the Retry-After header when the server provides one.
+ */
+export async function requestWithRetry<T = any>(
+  client: AxiosInstance,
+  config: AxiosRequestConfig,
+  maxRetries: number
+): Promise<AxiosResponse<T>> {
+  for (let attempt = 0; ; attempt++) {
+    try {
+      return await client.request<T>(config);
+    } catch (error) {
+      if (attempt >= maxRetries || !isRetryable(error)) {
+        throw error;
+      }
+
+      const delay = getRetryDelay(error, attempt);
+      const reason = axios.isAxiosError(error) && error.response
+        ? `HTTP ${error.response.status}`
+        : `${error}`;
+      core.info(
+        `${(config.method || 'get').toUpperCase()} ${redact(config.url)} failed (${redact(reason)}), retrying in ${delay}ms (attempt ${attempt + 1}/${maxRetries})`
+      );
+      await sleep(delay);
+    }
+  }
+}
+
+function isRetryable(error: unknown): boolean {
+  if (!axios.isAxiosError(error)) {
+    return false;
+  }
+
+  // Network errors and timeouts have no response
+  if (!error.response) {
+    return error.code !== 'ERR_CANCELED';
+  }
+
+  return RETRYABLE_STATUS_CODES.includes(error.response.status);
+}
+
+function getRetryDelay(error: unknown, attempt: number): number {
+  if (axios.isAxiosError(error)) {
+    const retryAfter = parseRetryAfter(error.response?.headers?.['retry-after']);
+    if (retryAfter !== undefined) {
+      return Math.min(retryAfter, MAX_DELAY_MS);
+    }
+  }
+
+  const backoff = Math.min(BASE_DELAY_MS * 2 ** attempt, MAX_DELAY_MS);
+  const jitter = Math.floor(Math.random() * BASE_DELAY_MS);
+  return backoff + jitter;
+}
+
+/**
+ * Parse a Retry-After header (seconds or HTTP date) into milliseconds
+ */
+export function parseRetryAfter(value: unknown): number | undefined {
+  if (typeof value !== 'string' || value.trim() === '') {
+    return undefined;
+  }
+
+  const seconds = Number(value);
+  if (!Number.isNaN(seconds)) {
+    return Math.max(0, seconds * 1000);
+  }
+
+  const date = Date.parse(value);
+  if (!Number.isNaN(date)) {
+    return Math.max(0, date - Date.now());
+  }
+
+  return undefined;
+}
+
+/**
+ * Replace the password in a URL so it can be logged safely
+ */
+export function maskUrlCredentials(value: string): string {
+  try {
+    const url = new URL(value);
+    if (url.password) {
+      url.password = '***';
+    }
+    return url.toString();
+  } catch {
+    return '***';
+  }
+}
+
+export function sleep(ms: number): Promise<void> {
+  return new Promise((resolve) => setTimeout(resolve, ms));
+}
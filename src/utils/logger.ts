@@ -0,0 +1,28 @@
+import * as core from '@actions/core';
+
+export type LogFormat = 'text' | 'json';
+
+export const LOG_FORMATS: LogFormat[] = ['text', 'json'];
+
+let logFormat: LogFormat = 'text';
+let correlationId: string | undefined;
+
+export function setLogFormat(format: LogFormat): void {
+  logFormat = format;
+}
+
+export function setCorrelationId(id: string): void {
+  correlationId = id;
+}
+
+/**
+ * Log a key event (status fetched, notification sent, cache saved). Text format prints the
+ * message as before; json prints a single line with the fields, for log aggregators.
+ */
+export function logEvent(event: string, message: string, fields: Record<string, unknown> = {}): void {
+  if (logFormat === 'json') {
+    core.info(JSON.stringify({ time: new Date().toISOString(), event, message, correlationId, ...fields }));
+  } else {
+    core.info(message);
+  }
+}
@@ -0,0 +1,137 @@
+import * as core from '@actions/core';
+import { MetricsConfig } from '../types';
+import { HttpRequester } from './http';
+import { redact } from './redact';
+import { StatusHistoryEntry, VersionCache, VersionCacheManager } from './versionCache';
+
+// Pushgateway job, grouped further by cache_key when set; each push replaces the previous run's series
+const PUSHGATEWAY_JOB = 'store-review-monitor';
+
+type Labels = Record<string, string>;
+
+interface Metric {
+  name: string;
+  help: string;
+  type: 'gauge' | 'counter';
+  samples: [Labels, number][];
+}
+
+interface MetricSource {
+  history?: StatusHistoryEntry[];
+  rejectionCount?: number;
+}
+
+/**
+ * Push review latency, rejection and version metrics for every cached app/track
+ * to a Prometheus pushgateway, in the text exposition format
+ */
+export async function pushMetrics(
+  config: MetricsConfig,
+  cache: VersionCache,
+  cacheManager: VersionCacheManager,
+  http: HttpRequester
+): Promise<void> {
+  const duration: Metric = {
+    name: 'store_review_status_duration_hours',
+    help: 'Hours spent in the current review status',
+    type: 'gauge',
+    samples: [],
+  };
+  const rejections: Metric = {
+    name: 'store_review_rejections_total',
+    help: 'Review rejections seen since monitoring started',
+    type: 'counter',
+    samples: [],
+  };
+  const versionCode: Metric = {
+    name: 'store_review_version_code',
+    help: 'Version code (Google Play, Amazon Appstore) or numeric build number (App Store) being reviewed',
+    type: 'gauge',
+    samples: [],
+  };
+
+  const now = new Date(cache.lastChecked);
+  const addEntry = (labels: Labels, entry: MetricSource, code?: number) => {
+    const seconds = cacheManager.getCurrentStatusDuration(entry.history || [], now);
+    if (seconds !== undefined) {
+      duration.samples.push([labels, Math.round((seconds / 3600) * 100) / 100]);
+    }
+    rejections.samples.push([labels, entry.rejectionCount ?? 0]);
+    if (code !== undefined) {
+      versionCode.samples.push([labels, code]);
+    }
+  };
+
+  for (const [platform, entries] of [
+    ['App Store', cache.appStore],
+    ['TestFlight', cache.testFlight],
+  ] as const) {
+    for (const entry of Object.values(entries || {})) {
+      addEntry(
+        { platform, app: entry.appId, app_platform: entry.platform || 'IOS' },
+        entry,
+        entry.buildNumber && /^\d+$/.test(entry.buildNumber) ? Number(entry.buildNumber) : undefined
+      );
+    }
+  }
+  for (const entry of Object.values(cache.googlePlay || {})) {
+    addEntry({ platform: 'Google Play', app: entry.packageName, track: entry.track }, entry, entry.versionCode);
+  }
+  for (const entry of Object.values(cache.amazon || {})) {
+    addEntry(
+      { platform: 'Amazon Appstore', app: entry.appId },
+      entry,
+      /^\d+$/.test(entry.version) ? Number(entry.version) : undefined
+    );
+  }
+  // AppGallery version numbers are version names (1.2.3), so there's no version code sample
+  for (const entry of Object.values(cache.huawei || {})) {
+    addEntry({ platform: 'Huawei AppGallery', app: entry.appId }, entry);
+  }
+
+  if (rejections.samples.length === 0) {
+    core.info('No cached apps or tracks, skipping metrics push');
+    return;
+  }
+
+  const body = formatMetrics([duration, rejections, versionCode]);
+
+  if (config.dryRun) {
+    core.info(redact(`[dry-run] Pushgateway metrics:\n${body}`));
+    return;
+  }
+
+  const group = config.cacheKey ? `/cache_key/${encodeURIComponent(config.cacheKey)}` : '';
+  await http.request({
+    method: 'put',
+    url: `${config.pushgatewayUrl.replace(/\/+$/, '')}/metrics/job/${PUSHGATEWAY_JOB}${group}`,
+    data: body,
+    headers: {
+      'Content-Type': 'text/plain; version=0.0.4',
+    },
+  });
+  core.info(`Pushed metrics for ${rejections.samples.length} app(s)/track(s) to the pushgateway`);
+}
+
+function formatMetrics(metrics: Metric[]): string {
+  const lines: string[] = [];
+  for (const metric of metrics) {
+    if (metric.samples.length === 0) {
+      continue;
+    }
+    lines.push(`# HELP ${metric.name} ${metric.help}`);
+    lines.push(`# TYPE ${metric.name} ${metric.type}`);
+    for (const [labels, value] of metric.samples) {
+      const labelText = Object.entries(labels)
+        .map(([name, labelValue]) => `${name}="${escapeLabelValue(labelValue)}"`)
+        .join(',');
+      lines.push(`${metric.name}{${labelText}} ${value}`);
+    }
+  }
+  // The exposition format requires a trailing newline
+  return `${lines.join('\n')}\n`;
+}
+
+function escapeLabelValue(value: string): string {
+  return value.replace(/\\/g, '\\\\').replace(/"/g, '\\"').replace(/\n/g, '\\n');
+}
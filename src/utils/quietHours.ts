@@ -0,0 +1,44 @@
+import { QuietHoursConfig } from '../types';
+
+const TIME_OF_DAY_PATTERN = /^([01]?\d|2[0-3]):([0-5]\d)$/;
+
+/**
+ * Parse an HH:MM time into minutes after midnight, or undefined when malformed
+ */
+export function parseTimeOfDay(value: string): number | undefined {
+  const match = value.trim().match(TIME_OF_DAY_PATTERN);
+  return match ? Number(match[1]) * 60 + Number(match[2]) : undefined;
+}
+
+/**
+ * Check an IANA time zone name (e.g. Asia/Tokyo) against the runtime's time zone data
+ */
+export function isValidTimeZone(timeZone: string): boolean {
+  try {
+    new Intl.DateTimeFormat('en-US', { timeZone });
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * Check if a time falls within the quiet hours window, which wraps past midnight when
+ * start is after end (e.g. 22:00-07:00). The start is inclusive and the end exclusive.
+ */
+export function isInQuietHours(date: Date, quietHours: QuietHoursConfig): boolean {
+  const parts = new Intl.DateTimeFormat('en-US', {
+    timeZone: quietHours.timeZone,
+    hour: '2-digit',
+    minute: '2-digit',
+    hourCycle: 'h23',
+  }).formatToParts(date);
+  const hour = Number(parts.find((part) => part.type === 'hour')?.value);
+  const minute = Number(parts.find((part) => part.type === 'minute')?.value);
+  const minutes = hour * 60 + minute;
+
+  if (quietHours.startMinutes <= quietHours.endMinutes) {
+    return minutes >= quietHours.startMinutes && minutes < quietHours.endMinutes;
+  }
+  return minutes >= quietHours.startMinutes || minutes < quietHours.endMinutes;
+}
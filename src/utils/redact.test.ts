@@ -0,0 +1,33 @@
+import { redact, registerSecret } from './redact';
+
+jest.mock('@actions/core');
+
+describe('redact', () => {
+  const token = 'abcdEFGHijklMNOPqrstUVWX';
+  const webhookUrl = `https://hooks.slack.com/services/T0123ABCD/B0456EFGH/${token}`;
+
+  beforeAll(() => {
+    registerSecret(webhookUrl);
+  });
+
+  it('scrubs an error quoting the full webhook URL', () => {
+    const message = redact(`Request to ${webhookUrl} failed with status 404`);
+    expect(message).not.toContain(token);
+    expect(message).toBe('Request to *** failed with status 404');
+  });
+
+  it('scrubs an error quoting only the webhook path', () => {
+    const message = redact(`POST /services/T0123ABCD/B0456EFGH/${token} returned invalid_token`);
+    expect(message).not.toContain(token);
+  });
+
+  it('scrubs an error quoting only the webhook token', () => {
+    const message = redact(`invalid_token: ${token}`);
+    expect(message).not.toContain(token);
+    expect(message).toBe('invalid_token: ***');
+  });
+
+  it('leaves short path segments of the URL in ordinary text', () => {
+    expect(redact('services are up')).toBe('services are up');
+  });
+});
@@ -0,0 +1,54 @@
+import * as core from '@actions/core';
+
+const REDACTED = '***';
+
+// Shorter parts of a secret (path prefixes like "services", Slack workspace IDs) would redact ordinary text
+const MIN_PART_LENGTH = 16;
+
+const secrets = new Set<string>();
+
+/**
+ * Register a secret input so redact() scrubs it, and mask it in the runner's log too.
+ * For URLs such as webhooks, the path and query are registered as well, so an error
+ * quoting only part of the URL can't reveal its token portion.
+ */
+export function registerSecret(value: string | undefined): void {
+  if (!value) {
+    return;
+  }
+
+  // Multi-line secrets such as private keys are scrubbed line by line as well
+  const parts = [value, ...value.split(/\r?\n/).map((line) => line.trim())];
+  try {
+    const url = new URL(value);
+    parts.push(
+      `${url.pathname}${url.search}`,
+      url.password,
+      ...url.pathname.split('/'),
+      ...Array.from(url.searchParams.values())
+    );
+  } catch {
+    // Not a URL, only the value itself is secret
+  }
+
+  for (const part of parts) {
+    if (part === value || part.length >= MIN_PART_LENGTH) {
+      secrets.add(part);
+      core.setSecret(part);
+    }
+  }
+}
+
+/**
+ * Scrub registered secrets from a value before it is logged, set as an output or included
+ * in an error. Longer secrets are replaced first, so a URL is redacted as a whole rather
+ * than leaving its host around a redacted path.
+ */
+export function redact(value: unknown): string {
+  let text = typeof value === 'string' ? value : `${value}`;
+  const sorted = Array.from(secrets).sort((a, b) => b.length - a.length);
+  for (const secret of sorted) {
+    text = text.split(secret).join(REDACTED);
+  }
+  return text;
+}
@@ -0,0 +1,280 @@
+import { NotificationPayload } from '../types';
+import { Messages } from '../types/i18n';
+
+/**
+ * Status classification shared by all notification channels
+ */
+
+// The app was pulled or its release stopped, as opposed to a review rejection
+const STOPPED_STATUSES = [
+  'removed_from_sale',
+  'developer_rejected',
+  'halted',
+];
+
+function isStoppedStatus(statusLower: string): boolean {
+  return STOPPED_STATUSES.some((s) => statusLower.includes(s));
+}
+
+// Approved, but waiting for someone to release it by hand
+const ACTION_REQUIRED_STATUSES = ['pending_developer_release'];
+
+export function isActionRequiredStatus(status: string): boolean {
+  const statusLower = status.toLowerCase();
+  return ACTION_REQUIRED_STATUSES.some((s) => statusLower.includes(s));
+}
+
+export function getStatusColor(status: string): string {
+  const statusLower = status.toLowerCase();
+
+  if (
+    statusLower.includes('approved') ||
+    statusLower.includes('ready_for_sale') ||
+    statusLower.includes('completed') ||
+    statusLower.includes('pending_developer_release') ||
+    // Amazon Appstore edit published
+    statusLower === 'live' ||
+    // Huawei AppGallery
+    statusLower === 'released' ||
+    // App Store review submission
+    statusLower === 'complete'
+  ) {
+    return 'good'; // Green
+  }
+
+  if (
+    isStoppedStatus(statusLower) ||
+    statusLower.includes('rejected') ||
+    statusLower.includes('invalid') ||
+    statusLower.includes('failed') ||
+    statusLower.includes('developer_action_needed') ||
+    // App Store review submission with rejected items
+    statusLower === 'unresolved_issues'
+  ) {
+    return 'danger'; // Red
+  }
+
+  if (
+    statusLower.includes('in_review') ||
+    statusLower.includes('beta_review') ||
+    statusLower.includes('processing') ||
+    // Google Play staged rollout
+    statusLower.includes('inprogress') ||
+    // Amazon Appstore edit in review
+    statusLower === 'submitted' ||
+    // Huawei AppGallery release or update on its way
+    statusLower === 'releasing' ||
+    statusLower === 'updating'
+  ) {
+    return 'warning'; // Yellow
+  }
+
+  return '#808080'; // Gray
+}
+
+/**
+ * Hex color for channels that don't understand Slack's named colors
+ */
+export function getStatusHexColor(status: string): string {
+  switch (getStatusColor(status)) {
+    case 'good':
+      return '#2EB886';
+    case 'danger':
+      return '#A30200';
+    case 'warning':
+      return '#DAA038';
+    default:
+      return '#808080';
+  }
+}
+
+/**
+ * Status category for automation: good, warning, danger, or info for the gray statuses
+ */
+export function getStatusCategory(status: string): string {
+  const color = getStatusColor(status);
+  return color === 'good' || color === 'warning' || color === 'danger' ? color : 'info';
+}
+
+// From status-emoji-map: lowercase status substring -> emoji or Slack shortcode
+let statusEmojiMap: Record<string, string> = {};
+
+export function setStatusEmojiMap(map: Record<string, string>): void {
+  statusEmojiMap = map;
+}
+
+export function getStatusEmoji(status: string): string {
+  const statusLower = status.toLowerCase();
+
+  // The longest matching key wins, so metadata_rejected can differ from rejected
+  const override = Object.keys(statusEmojiMap)
+    .filter((key) => statusLower.includes(key))
+    .sort((a, b) => b.length - a.length)[0];
+  if (override) {
+    return statusEmojiMap[override];
+  }
+
+  if (isStoppedStatus(statusLower)) {
+    return '🛑';
+  }
+
+  if (isActionRequiredStatus(statusLower)) {
+    return '🚀';
+  }
+
+  if (
+    statusLower.includes('approved') ||
+    statusLower.includes('ready_for_sale') ||
+    statusLower.includes('completed') ||
+    statusLower === 'live' ||
+    statusLower === 'released' ||
+    statusLower === 'complete'
+  ) {
+    return '✅';
+  }
+
+  if (
+    statusLower.includes('rejected') ||
+    statusLower.includes('invalid') ||
+    statusLower.includes('failed') ||
+    statusLower.includes('developer_action_needed') ||
+    statusLower === 'unresolved_issues'
+  ) {
+    return '❌';
+  }
+
+  if (
+    statusLower.includes('in_review') ||
+    statusLower.includes('beta_review') ||
+    statusLower.includes('processing') ||
+    statusLower.includes('inprogress') ||
+    statusLower === 'submitted' ||
+    statusLower === 'releasing' ||
+    statusLower === 'updating'
+  ) {
+    return '⏳';
+  }
+
+  // Google Play or Huawei AppGallery release, or Amazon Appstore edit, that hasn't been sent for review yet
+  if (statusLower === 'draft' || statusLower === 'in_progress') {
+    return '📝';
+  }
+
+  return 'ℹ️';
+}
+
+export function formatStatus(status: string): string {
+  // Google Play statuses are camelCase (inProgress), App Store ones SCREAMING_SNAKE_CASE
+  return status
+    .replace(/([a-z])([A-Z])/g, '$1_$2')
+    .split('_')
+    .map((word) => word.charAt(0).toUpperCase() + word.slice(1).toLowerCase())
+    .join(' ');
+}
+
+// App Store phased releases always span 7 days
+const PHASED_RELEASE_DAYS = 7;
+
+/**
+ * Notification title, naming the app when its name is known, e.g. "App Store Review Status Update · My App"
+ */
+export function formatTitle(payload: NotificationPayload, messages: Messages): string {
+  return `${payload.platform} ${messages.reviewStatusUpdate}${payload.appName ? ` · ${payload.appName}` : ''}`;
+}
+
+// From display-timezone (IANA name); the cache keeps UTC regardless
+let displayTimeZone = 'UTC';
+
+export function setDisplayTimeZone(timeZone: string): void {
+  displayTimeZone = timeZone;
+}
+
+/**
+ * Wall-clock date and time in the display time zone, and its offset from UTC in minutes
+ */
+function getDisplayParts(date: Date): { date: string; time: string; offsetMinutes: number } {
+  const parts = new Intl.DateTimeFormat('en-US', {
+    timeZone: displayTimeZone,
+    year: 'numeric',
+    month: '2-digit',
+    day: '2-digit',
+    hour: '2-digit',
+    minute: '2-digit',
+    second: '2-digit',
+    hourCycle: 'h23',
+  }).formatToParts(date);
+  const part = (type: string) => parts.find((p) => p.type === type)?.value || '00';
+
+  const wallClock = Date.UTC(
+    Number(part('year')),
+    Number(part('month')) - 1,
+    Number(part('day')),
+    Number(part('hour')),
+    Number(part('minute')),
+    Number(part('second'))
+  );
+  return {
+    date: `${part('year')}-${part('month')}-${part('day')}`,
+    time: `${part('hour')}:${part('minute')}:${part('second')}`,
+    offsetMinutes: Math.round((wallClock - Math.floor(date.getTime() / 1000) * 1000) / 60000),
+  };
+}
+
+/**
+ * Format the time of a check as RFC 3339 in the display time zone,
+ * e.g. "2026-10-01T18:30:00+09:00", or "2026-10-01T09:30:00.000Z" in UTC
+ */
+export function formatCheckedAt(date: Date): string {
+  if (displayTimeZone === 'UTC') {
+    return date.toISOString();
+  }
+
+  const { date: day, time, offsetMinutes } = getDisplayParts(date);
+  const sign = offsetMinutes < 0 ? '-' : '+';
+  const hours = String(Math.floor(Math.abs(offsetMinutes) / 60)).padStart(2, '0');
+  const minutes = String(Math.abs(offsetMinutes) % 60).padStart(2, '0');
+  return `${day}T${time}${sign}${hours}:${minutes}`;
+}
+
+/**
+ * Format an ISO 8601 timestamp in the display time zone, e.g. "2026-10-01 09:30 UTC" or
+ * "2026-10-01 18:30 Asia/Tokyo". Unparsable values are returned unchanged.
+ */
+export function formatTimestamp(value: string): string {
+  const time = Date.parse(value);
+  if (Number.isNaN(time)) {
+    return value;
+  }
+
+  const { date, time: clock } = getDisplayParts(new Date(time));
+  return `${date} ${clock.slice(0, 5)} ${displayTimeZone}`;
+}
+
+/**
+ * Describe the App Store release type and phased release progress, e.g.
+ * "After Approval · Phased release day 3 of 7"
+ */
+export function formatReleaseInfo(payload: NotificationPayload, messages: Messages): string | undefined {
+  const parts: string[] = [];
+
+  if (payload.releaseType) {
+    parts.push(
+      payload.releaseType === 'SCHEDULED' && payload.earliestReleaseDate
+        ? `${formatStatus(payload.releaseType)} (${formatTimestamp(payload.earliestReleaseDate)})`
+        : formatStatus(payload.releaseType)
+    );
+  }
+
+  const phased = payload.phasedRelease;
+  if (phased) {
+    let text = phased.currentDay
+      ? messages.phasedReleaseDay(phased.currentDay, PHASED_RELEASE_DAYS)
+      : messages.phasedRelease;
+    if (phased.state !== 'ACTIVE') {
+      text += ` (${formatStatus(phased.state)})`;
+    }
+    parts.push(text);
+  }
+
+  return parts.length > 0 ? parts.join(' · ') : undefined;
+}
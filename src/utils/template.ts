@@ -0,0 +1,58 @@
+/**
+ * Minimal renderer for Go text/template style placeholders ({{.Field}}),
+ * so templates can be shared with the Bitrise step
+ */
+
+const PLACEHOLDER_PATTERN = /\{\{\s*\.(\w+)\s*\}\}/g;
+
+// Fields available to slack-template
+export const SLACK_TEMPLATE_FIELDS = [
+  'Platform',
+  'Version',
+  'CurrentStatus',
+  'PreviousStatus',
+  'Emoji',
+  'CheckedAt',
+  'AppName',
+  'RejectionReason',
+  'Release',
+  'Rollout',
+  'Submitted',
+  'DaysInReview',
+  'ConsoleUrl',
+  'Mentions',
+];
+
+// Fields available to slack-footer-text
+export const SLACK_FOOTER_FIELDS = ['Platform', 'AppName', 'Version', 'BuildUrl'];
+
+/**
+ * URL of the running GitHub Actions run or Bitrise build, when there is one
+ */
+export function getBuildUrl(): string | undefined {
+  const { GITHUB_SERVER_URL, GITHUB_REPOSITORY, GITHUB_RUN_ID, BITRISE_BUILD_URL } = process.env;
+  if (GITHUB_SERVER_URL && GITHUB_REPOSITORY && GITHUB_RUN_ID) {
+    return `${GITHUB_SERVER_URL}/${GITHUB_REPOSITORY}/actions/runs/${GITHUB_RUN_ID}`;
+  }
+  return BITRISE_BUILD_URL || undefined;
+}
+
+/**
+ * Throw if the template references a field that isn't in allowedFields
+ */
+export function validateTemplate(template: string, allowedFields: string[]): void {
+  for (const match of template.matchAll(PLACEHOLDER_PATTERN)) {
+    if (!allowedFields.includes(match[1])) {
+      throw new Error(
+        `Unknown template field ".${match[1]}" (available: ${allowedFields.map((f) => `.${f}`).join(', ')})`
+      );
+    }
+  }
+}
+
+/**
+ * Replace each {{.Field}} with its value, rendering missing values as empty strings
+ */
+export function renderTemplate(template: string, fields: Record<string, string | undefined>): string {
+  return template.replace(PLACEHOLDER_PATTERN, (_, name: string) => fields[name] ?? '');
+}
@@ -1,58 +1,319 @@
 import * as core from '@actions/core';
 import * as artifact from '@actions/artifact';
+import axios from 'axios';
 import * as fs from 'fs';
 import * as path from 'path';
+import { CacheConfig, FlappingConfig, GistCacheConfig, NotificationPayload } from '../types';
+import { HttpRequester } from './http';
+import { logEvent } from './logger';
+import { redact } from './redact';
+
+export interface StatusHistoryEntry {
+  status: string;
+  version: string;
+  build?: string;
+  timestamp: string;
+}
+
+export interface SlackThreadEntry {
+  // Version the thread (or message updated in place) belongs to; a new version starts a new one
+  version: string;
+  ts: string;
+}
+
+export interface LastNotificationEntry {
+  status: string;
+  version: string;
+  timestamp: string;
+}
+
+// Per-entry state used when sending notifications
+export interface NotifiableCacheEntry {
+  slackThread?: SlackThreadEntry;
+  lastNotification?: LastNotificationEntry;
+  // Idempotency key of the last message posted with the Slack bot token
+  slackMessageKey?: string;
+}
+
+export interface AppStoreCacheEntry {
+  appId: string;
+  // Fetched once and reused while the app ID stays the same
+  appName?: string;
+  // Set to IOS by the schema 1 migration for caches written before multi-platform support
+  platform?: string;
+  version: string;
+  buildNumber?: string;
+  status: string;
+  history?: StatusHistoryEntry[];
+  // Rejections seen across runs, unlike history which is trimmed to history-limit
+  rejectionCount?: number;
+  // Runs in a row that saw the same version/build and status
+  consecutiveUnchangedRuns?: number;
+  // Last version seen READY_FOR_SALE, so going live is only announced once per version
+  liveVersion?: string;
+  slackThread?: SlackThreadEntry;
+  lastNotification?: LastNotificationEntry;
+  slackMessageKey?: string;
+}
+
+// TestFlight entries share the App Store shape, with buildNumber identifying the beta build
+export type TestFlightCacheEntry = AppStoreCacheEntry;
+
+// Kept apart from AppStoreCacheEntry so processing changes aren't mistaken for appStoreState changes
+export interface BuildProcessingCacheEntry {
+  appId: string;
+  platform: string;
+  version: string;
+  buildNumber: string;
+  processingState: string;
+  slackThread?: SlackThreadEntry;
+  lastNotification?: LastNotificationEntry;
+  slackMessageKey?: string;
+}
+
+// Kept apart from AppStoreCacheEntry, with submissionId telling a resubmission of the same
+// version apart from the submission it replaced
+export interface ReviewSubmissionCacheEntry {
+  appId: string;
+  platform: string;
+  submissionId: string;
+  // App Store version string, or the submission ID when it has no version
+  version: string;
+  status: string;
+  history?: StatusHistoryEntry[];
+  slackThread?: SlackThreadEntry;
+  lastNotification?: LastNotificationEntry;
+  slackMessageKey?: string;
+}
+
+export interface InAppPurchaseCacheEntry {
+  appId: string;
+  productId: string;
+  kind: string;
+  state: string;
+  slackThread?: SlackThreadEntry;
+  lastNotification?: LastNotificationEntry;
+  slackMessageKey?: string;
+}
+
+export interface GooglePlayCacheEntry {
+  packageName: string;
+  // Listing title, reused by every track of the package instead of reading the listing each run
+  appName?: string;
+  track: string;
+  versionCode: number;
+  versionName?: string;
+  status: string;
+  userFraction?: number;
+  history?: StatusHistoryEntry[];
+  rejectionCount?: number;
+  consecutiveUnchangedRuns?: number;
+  slackThread?: SlackThreadEntry;
+  lastNotification?: LastNotificationEntry;
+  slackMessageKey?: string;
+}
+
+export interface AmazonCacheEntry {
+  appId: string;
+  editId?: string;
+  version: string;
+  status: string;
+  history?: StatusHistoryEntry[];
+  rejectionCount?: number;
+  consecutiveUnchangedRuns?: number;
+  slackThread?: SlackThreadEntry;
+  lastNotification?: LastNotificationEntry;
+  slackMessageKey?: string;
+}
+
+export interface HuaweiCacheEntry {
+  appId: string;
+  appName?: string;
+  version: string;
+  status: string;
+  history?: StatusHistoryEntry[];
+  rejectionCount?: number;
+  consecutiveUnchangedRuns?: number;
+  slackThread?: SlackThreadEntry;
+  lastNotification?: LastNotificationEntry;
+  slackMessageKey?: string;
+}
 
 export interface VersionCache {
-  appStore?: {
-    appId: string;
-    version: string;
-    buildNumber?: string;
-    status: string;
-  };
-  googlePlay?: {
-    packageName: string;
-    versionCode: number;
-    versionName?: string;
-    status: string;
-  };
+  // Unset in caches written before schema versioning (schema 1)
+  schemaVersion?: number;
+  // Keyed by App Store app ID, suffixed with the platform for non-iOS platforms
+  appStore?: Record<string, AppStoreCacheEntry>;
+  // Keyed by App Store app ID, kept apart from production review state
+  testFlight?: Record<string, TestFlightCacheEntry>;
+  // Keyed by App Store app ID, holding the latest uploaded build's processing state
+  buildProcessing?: Record<string, BuildProcessingCacheEntry>;
+  // Keyed by <appId>/<productId>
+  inAppPurchases?: Record<string, InAppPurchaseCacheEntry>;
+  // Keyed by App Store app ID, holding the latest review submission's state
+  reviewSubmissions?: Record<string, ReviewSubmissionCacheEntry>;
+  // Keyed by Google Play track name
+  googlePlay?: Record<string, GooglePlayCacheEntry>;
+  // Keyed by Amazon Appstore app ID
+  amazon?: Record<string, AmazonCacheEntry>;
+  // Keyed by AppGallery Connect app ID
+  huawei?: Record<string, HuaweiCacheEntry>;
+  // Notifications suppressed during quiet hours, sent by the first run after them
+  heldNotifications?: NotificationPayload[];
+  // Platforms whose credentials were rejected and already alerted about
+  authFailureAlerts?: string[];
+  // When the last heartbeat-interval-hours message was sent
+  lastHeartbeat?: string;
   lastChecked: string;
 }
 
+// Matched as case-insensitive substrings of the review status. The rejected and approved
+// lists can be replaced with the rejected-statuses and approved-statuses inputs.
+const DEFAULT_REJECTED_STATUSES = ['rejected'];
+const DEFAULT_APPROVED_STATUSES = [
+  'ready_for_sale',
+  'pending_developer_release',
+  'pending_apple_release',
+  'completed',
+  // TestFlight beta review
+  'approved',
+  // Amazon Appstore edit published
+  'live',
+  // Huawei AppGallery
+  'released',
+];
+// Pulled from sale or halted rollout (Google Play)
+const REMOVED_STATUSES = ['removed_from_sale', 'halted'];
+
+export type CachePlatform = 'appStore' | 'testFlight' | 'googlePlay' | 'amazon' | 'huawei';
+
+function matchesAny(status: string, candidates: string[]): boolean {
+  const statusLower = status.toLowerCase();
+  return candidates.some((candidate) => statusLower.includes(candidate));
+}
+
+// Bump when the cache shape changes, adding a migration from the previous version
+export const CACHE_SCHEMA_VERSION = 2;
+
+// Migrations keyed by the schema version they upgrade from
+const CACHE_MIGRATIONS: Record<number, (cache: any) => any> = {
+  // Single entry per platform before multi-app and multi-track support, no history or platform
+  1: (cache) => {
+    if (cache.appStore && typeof cache.appStore.appId === 'string') {
+      cache.appStore = { [cache.appStore.appId]: cache.appStore };
+    }
+    if (cache.googlePlay && typeof cache.googlePlay.packageName === 'string') {
+      cache.googlePlay = { production: { ...cache.googlePlay, track: 'production' } };
+    }
+    for (const entry of Object.values<any>(cache.appStore || {})) {
+      entry.platform = entry.platform || 'IOS';
+      entry.history = entry.history || [];
+    }
+    for (const entry of Object.values<any>(cache.googlePlay || {})) {
+      entry.history = entry.history || [];
+    }
+    return cache;
+  },
+};
+
+/**
+ * Upgrade a parsed cache to CACHE_SCHEMA_VERSION. Returns null for caches written by a newer
+ * version of the action, which can't be interpreted safely.
+ */
+export function migrateCache(cache: any): VersionCache | null {
+  const schemaVersion = typeof cache.schemaVersion === 'number' ? cache.schemaVersion : 1;
+  if (schemaVersion > CACHE_SCHEMA_VERSION) {
+    core.warning(
+      `Version cache schema ${schemaVersion} is newer than supported (${CACHE_SCHEMA_VERSION}), starting with an empty cache`
+    );
+    return null;
+  }
+
+  for (let version = schemaVersion; version < CACHE_SCHEMA_VERSION; version++) {
+    core.info(`Migrating version cache from schema ${version} to ${version + 1}`);
+    cache = CACHE_MIGRATIONS[version](cache);
+  }
+  cache.schemaVersion = CACHE_SCHEMA_VERSION;
+  return cache as VersionCache;
+}
+
 const ARTIFACT_NAME = 'store-review-versions';
 const CACHE_FILE_NAME = 'versions.json';
+// Previous run's cache, uploaded alongside the current one to recover from a corrupt file
+const BACKUP_FILE_NAME = `${CACHE_FILE_NAME}.bak`;
+const DEFAULT_GITHUB_API_URL = 'https://api.github.com';
 
 export class VersionCacheManager {
   private artifactClient = artifact.create();
+  private config: CacheConfig;
+  private http: HttpRequester;
+  private artifactName: string;
+  private gistFileName: string;
+  // Cache file the previous versions were read from, kept as the next backup
+  private loadedFilePath?: string;
+  private rejectedStatuses: string[];
+  private approvedStatuses: string[];
+
+  constructor(config: CacheConfig, http: HttpRequester) {
+    this.config = config;
+    this.http = http;
+    this.artifactName = config.key ? `${ARTIFACT_NAME}-${config.key}` : ARTIFACT_NAME;
+    this.gistFileName = config.key ? `versions-${config.key}.json` : CACHE_FILE_NAME;
+    this.rejectedStatuses = config.rejectedStatuses || DEFAULT_REJECTED_STATUSES;
+    this.approvedStatuses = config.approvedStatuses || DEFAULT_APPROVED_STATUSES;
+  }
 
   /**
-   * Load the previous version cache from artifact
+   * Check if a status counts as a rejection (rejected-statuses, or the defaults)
+   */
+  isRejectedStatus(status: string): boolean {
+    return matchesAny(status, this.rejectedStatuses);
+  }
+
+  /**
+   * Load the previous version cache from gist-id or cache-url when set, otherwise from the artifact
    */
   async loadPreviousVersions(): Promise<VersionCache | null> {
+    if (this.config.gist) {
+      return this.loadFromGist(this.config.gist);
+    }
+    if (this.config.url) {
+      return this.loadFromUrl(this.config.url);
+    }
+
     try {
-      core.info('Loading previous version cache from artifact...');
+      core.info(`Loading previous version cache from artifact ${this.artifactName}...`);
 
-      // Create a temporary directory for downloading
-      const downloadPath = path.join(process.cwd(), '.version-cache');
+      // Create a temporary directory for downloading, one per artifact so monitors in the same job don't share files
+      const downloadPath = path.join(process.cwd(), `.${this.artifactName}`);
       if (!fs.existsSync(downloadPath)) {
         fs.mkdirSync(downloadPath, { recursive: true });
       }
 
       // Download the artifact
       const downloadResult = await this.artifactClient.downloadArtifact(
-        ARTIFACT_NAME,
+        this.artifactName,
         downloadPath
       );
 
       core.info(`Artifact downloaded to: ${downloadResult.downloadPath}`);
 
-      // Read the cache file
-      const cacheFilePath = path.join(downloadPath, CACHE_FILE_NAME);
-      if (fs.existsSync(cacheFilePath)) {
-        const cacheContent = fs.readFileSync(cacheFilePath, 'utf-8');
-        const cache = JSON.parse(cacheContent) as VersionCache;
-        core.info(`Loaded previous versions: ${JSON.stringify(cache)}`);
-        return cache;
+      // Read the cache file, falling back to the backup if it is missing or corrupt
+      for (const fileName of [CACHE_FILE_NAME, BACKUP_FILE_NAME]) {
+        const cacheFilePath = path.join(downloadPath, fileName);
+        if (!fs.existsSync(cacheFilePath)) {
+          continue;
+        }
+
+        try {
+          const cache = this.parseCache(fs.readFileSync(cacheFilePath, 'utf-8'), fileName);
+          if (cache) {
+            this.loadedFilePath = cacheFilePath;
+          }
+          return cache;
+        } catch (error) {
+          core.warning(`Failed to parse ${fileName}: ${error}`);
+        }
       }
 
       core.info('No cache file found in artifact');
@@ -68,35 +329,163 @@ export class VersionCacheManager {
   }
 
   /**
-   * Save the current version cache to artifact
+   * Load the previous version cache with a GET request, treating 404 as no cache (first run).
+   * There's no backup copy to fall back to, so a corrupt cache starts an empty one.
+   */
+  private async loadFromUrl(url: string): Promise<VersionCache | null> {
+    try {
+      core.info(`Loading previous version cache from ${redact(url)}...`);
+      const response = await this.http.request<string>({
+        method: 'get',
+        url: url,
+        // Parsed and migrated here rather than by axios
+        responseType: 'text',
+      });
+      const content = typeof response.data === 'string' ? response.data : JSON.stringify(response.data);
+      return this.parseCache(content, redact(url));
+    } catch (error) {
+      if (axios.isAxiosError(error) && error.response?.status === 404) {
+        core.info('No previous cache found at cache-url (first run)');
+      } else {
+        core.warning(`Failed to load previous versions: ${redact(error)}`);
+      }
+      return null;
+    }
+  }
+
+  /**
+   * Load the previous version cache from the gist's cache file, treating a missing file as no
+   * cache (first run). Like cache-url, there's no backup copy to fall back to.
+   */
+  private async loadFromGist(gist: GistCacheConfig): Promise<VersionCache | null> {
+    try {
+      core.info(`Loading previous version cache from gist ${gist.gistId}...`);
+      const response = await this.http.request({
+        method: 'get',
+        url: getGistUrl(gist),
+        headers: getGistHeaders(gist),
+      });
+
+      const file = response.data?.files?.[this.gistFileName];
+      if (!file) {
+        core.info(`No ${this.gistFileName} in gist ${gist.gistId} (first run)`);
+        return null;
+      }
+
+      // The API truncates file contents over 1 MB, leaving the full file at raw_url
+      let content: string = file.content;
+      if (file.truncated) {
+        const rawResponse = await this.http.request<string>({
+          method: 'get',
+          url: file.raw_url,
+          headers: { Authorization: `Bearer ${gist.token}` },
+          responseType: 'text',
+        });
+        content = rawResponse.data;
+      }
+      return this.parseCache(content, `gist ${gist.gistId}`);
+    } catch (error) {
+      if (axios.isAxiosError(error) && error.response?.status === 404) {
+        core.warning(`Gist ${gist.gistId} not found. Check gist-id and that github-token has the gist scope`);
+      } else {
+        core.warning(`Failed to load previous versions: ${redact(error)}`);
+      }
+      return null;
+    }
+  }
+
+  /**
+   * Parse and migrate a cache file's contents, warning when it is older than cache-max-age-hours
+   */
+  private parseCache(content: string, source: string): VersionCache | null {
+    const cache = migrateCache(JSON.parse(content));
+    if (!cache) {
+      return null;
+    }
+    core.info(`Loaded previous versions from ${source}: ${JSON.stringify(cache)}`);
+
+    if (this.isCacheStale(cache, new Date())) {
+      core.warning(
+        `Previous version cache is ${this.getCacheAgeHours(cache, new Date())} hours old (cache-max-age-hours: ${this.config.maxAgeHours}), monitoring may have lapsed`
+      );
+    }
+    return cache;
+  }
+
+  /**
+   * Hours since the cache was written, rounded to one decimal
+   */
+  getCacheAgeHours(cache: VersionCache, now: Date): number | undefined {
+    const lastChecked = Date.parse(cache.lastChecked);
+    if (Number.isNaN(lastChecked)) {
+      return undefined;
+    }
+
+    return Math.max(0, Math.round((now.getTime() - lastChecked) / 360000) / 10);
+  }
+
+  /**
+   * Check if the cache is older than maxAgeHours, which suggests scheduled runs stopped for a while
+   */
+  isCacheStale(cache: VersionCache, now: Date): boolean {
+    const age = this.getCacheAgeHours(cache, now);
+    return this.config.maxAgeHours > 0 && age !== undefined && age > this.config.maxAgeHours;
+  }
+
+  /**
+   * Save the current version cache to gist-id or cache-upload-url (or cache-url) when set, otherwise to the artifact
    */
   async saveCurrentVersions(cache: VersionCache): Promise<void> {
+    if (this.config.gist) {
+      return this.saveToGist(this.config.gist, cache);
+    }
+
+    const uploadUrl = this.config.uploadUrl || this.config.url;
+    if (uploadUrl) {
+      return this.saveToUrl(uploadUrl, cache);
+    }
+
+    const startedAt = Date.now();
     try {
-      core.info('Saving current version cache to artifact...');
+      core.info(`Saving current version cache to artifact ${this.artifactName}...`);
 
       // Create a temporary directory for uploading
-      const uploadPath = path.join(process.cwd(), '.version-cache-upload');
+      const uploadPath = path.join(process.cwd(), `.${this.artifactName}-upload`);
       if (!fs.existsSync(uploadPath)) {
         fs.mkdirSync(uploadPath, { recursive: true });
       }
 
-      // Write the cache file
+      // Write to a temp file and rename it into place, so an interrupted run never leaves a truncated cache
       const cacheFilePath = path.join(uploadPath, CACHE_FILE_NAME);
-      fs.writeFileSync(cacheFilePath, JSON.stringify(cache, null, 2), 'utf-8');
+      const tempFilePath = `${cacheFilePath}.tmp`;
+      fs.writeFileSync(tempFilePath, JSON.stringify(cache, null, 2), 'utf-8');
+      fs.renameSync(tempFilePath, cacheFilePath);
 
       core.info(`Cache file created at: ${cacheFilePath}`);
 
+      const files = [cacheFilePath];
+
+      // Keep the last readable previous cache as a backup
+      if (this.loadedFilePath && fs.existsSync(this.loadedFilePath)) {
+        const backupFilePath = path.join(uploadPath, BACKUP_FILE_NAME);
+        fs.copyFileSync(this.loadedFilePath, backupFilePath);
+        files.push(backupFilePath);
+      }
+
       // Upload the artifact
       const uploadResult = await this.artifactClient.uploadArtifact(
-        ARTIFACT_NAME,
-        [cacheFilePath],
+        this.artifactName,
+        files,
         uploadPath,
         {
           continueOnError: false,
         }
       );
 
-      core.info(`Artifact uploaded successfully: ${uploadResult.artifactName}`);
+      logEvent('cache_saved', `Artifact uploaded successfully: ${uploadResult.artifactName}`, {
+        artifactName: uploadResult.artifactName,
+        durationMs: Date.now() - startedAt,
+      });
 
       // Clean up temporary directory
       fs.rmSync(uploadPath, { recursive: true, force: true });
@@ -105,72 +494,120 @@ export class VersionCacheManager {
     }
   }
 
+  private async saveToUrl(url: string, cache: VersionCache): Promise<void> {
+    const startedAt = Date.now();
+    try {
+      core.info(`Saving current version cache to ${redact(url)}...`);
+      await this.http.request({
+        method: this.config.uploadMethod,
+        url: url,
+        data: JSON.stringify(cache, null, 2),
+        headers: {
+          'Content-Type': 'application/json',
+        },
+      });
+
+      logEvent('cache_saved', `Version cache uploaded to ${redact(url)}`, {
+        url: redact(url),
+        durationMs: Date.now() - startedAt,
+      });
+    } catch (error) {
+      core.warning(`Failed to save current versions: ${redact(error)}`);
+    }
+  }
+
+  /**
+   * Write the cache file of the gist, creating the file when it doesn't exist yet
+   */
+  private async saveToGist(gist: GistCacheConfig, cache: VersionCache): Promise<void> {
+    const startedAt = Date.now();
+    try {
+      core.info(`Saving current version cache to gist ${gist.gistId}...`);
+      await this.http.request({
+        method: 'patch',
+        url: getGistUrl(gist),
+        data: { files: { [this.gistFileName]: { content: JSON.stringify(cache, null, 2) } } },
+        headers: { ...getGistHeaders(gist), 'Content-Type': 'application/json' },
+      });
+
+      logEvent('cache_saved', `Version cache saved to gist ${gist.gistId}`, {
+        gistId: gist.gistId,
+        durationMs: Date.now() - startedAt,
+      });
+    } catch (error) {
+      core.warning(`Failed to save current versions: ${redact(error)}`);
+    }
+  }
+
   /**
    * Check if the version or build has changed
    */
   hasVersionOrBuildChanged(
-    platform: 'appStore' | 'googlePlay',
+    platform: CachePlatform,
     currentVersion: string | number,
-    currentBuild?: string | number,
-    previousCache: VersionCache | null
+    currentBuild: string | number | undefined,
+    previousData: AppStoreCacheEntry | GooglePlayCacheEntry | AmazonCacheEntry | HuaweiCacheEntry | undefined
   ): boolean {
-    if (!previousCache) {
-      core.info(`No previous cache found for ${platform}, treating as changed`);
-      return true;
-    }
-
-    const previousData = previousCache[platform];
     if (!previousData) {
       core.info(`No previous data found for ${platform}, treating as changed`);
       return true;
     }
 
-    if (platform === 'appStore') {
-      const versionChanged = previousData.version !== currentVersion;
-      const buildChanged = currentBuild && previousData.buildNumber !== currentBuild;
-      const changed = versionChanged || buildChanged;
+    if (platform === 'googlePlay') {
+      const previous = previousData as GooglePlayCacheEntry;
+      const versionChanged = previous.versionCode !== currentVersion;
       core.info(
-        `App Store comparison: v${previousData.version}(${previousData.buildNumber}) vs v${currentVersion}(${currentBuild}) - Changed: ${changed}`
+        `Google Play ${previous.track} version comparison: ${previous.versionCode} vs ${currentVersion} - Changed: ${versionChanged}`
       );
-      return changed;
-    } else {
-      const versionChanged = previousData.versionCode !== currentVersion;
+      return versionChanged;
+    } else if (platform === 'amazon' || platform === 'huawei') {
+      const previous = previousData as AmazonCacheEntry | HuaweiCacheEntry;
+      const versionChanged = previous.version !== currentVersion;
       core.info(
-        `Google Play version comparison: ${previousData.versionCode} vs ${currentVersion} - Changed: ${versionChanged}`
+        `${platform === 'amazon' ? 'Amazon Appstore' : 'Huawei AppGallery'} version comparison: ${previous.version} vs ${currentVersion} - Changed: ${versionChanged}`
       );
       return versionChanged;
+    } else {
+      const previous = previousData as AppStoreCacheEntry;
+      const versionChanged = previous.version !== currentVersion;
+      const buildChanged = !!currentBuild && previous.buildNumber !== currentBuild;
+      const changed = versionChanged || buildChanged;
+      core.info(
+        `${platform === 'testFlight' ? 'TestFlight' : 'App Store'} comparison: v${previous.version}(${previous.buildNumber}) vs v${currentVersion}(${currentBuild}) - Changed: ${changed}`
+      );
+      return changed;
     }
   }
 
   /**
-   * Check if status changed from REJECTED to approved status
+   * Check if the status differs from the previous run
    */
-  hasRecoveredFromRejection(
-    platform: 'appStore' | 'googlePlay',
+  hasStatusChanged(
     currentStatus: string,
-    previousCache: VersionCache | null
+    previousData: AppStoreCacheEntry | GooglePlayCacheEntry | AmazonCacheEntry | HuaweiCacheEntry | undefined
   ): boolean {
-    if (!previousCache) {
-      return false;
-    }
+    return !!previousData && previousData.status !== currentStatus;
+  }
 
-    const previousData = previousCache[platform];
+  /**
+   * Check if status changed from REJECTED, removed or halted to an approved status
+   */
+  hasRecoveredFromRejection(
+    platform: CachePlatform,
+    currentStatus: string,
+    previousData: AppStoreCacheEntry | GooglePlayCacheEntry | AmazonCacheEntry | HuaweiCacheEntry | undefined
+  ): boolean {
     if (!previousData) {
       return false;
     }
 
     const previousStatus = previousData.status.toLowerCase();
-    const currentStatusLower = currentStatus.toLowerCase();
 
-    // Check if previous status was rejected
-    const wasRejected = previousStatus.includes('rejected');
+    // Check if previous status was rejected, or the app was pulled and is now re-released
+    const wasRejected = matchesAny(previousStatus, [...this.rejectedStatuses, ...REMOVED_STATUSES]);
 
     // Check if current status is approved/success
-    const isApproved =
-      currentStatusLower.includes('ready_for_sale') ||
-      currentStatusLower.includes('pending_developer_release') ||
-      currentStatusLower.includes('pending_apple_release') ||
-      currentStatusLower.includes('completed');
+    const isApproved = matchesAny(currentStatus, this.approvedStatuses);
 
     const recovered = wasRejected && isApproved;
     if (recovered) {
@@ -179,4 +616,122 @@ export class VersionCacheManager {
 
     return recovered;
   }
+
+  /**
+   * Running rejection count for an entry, incremented when the status becomes rejected
+   * or a new version/build is rejected
+   */
+  countRejections(
+    currentStatus: string,
+    versionOrBuildChanged: boolean,
+    previousData: AppStoreCacheEntry | GooglePlayCacheEntry | AmazonCacheEntry | HuaweiCacheEntry | undefined
+  ): number {
+    const previousCount = previousData?.rejectionCount ?? 0;
+    const newlyRejected =
+      this.isRejectedStatus(currentStatus) &&
+      (!previousData || versionOrBuildChanged || !this.isRejectedStatus(previousData.status));
+    return previousCount + (newlyRejected ? 1 : 0);
+  }
+
+  /**
+   * Runs in a row with the same version/build and status, 0 when this run saw a change
+   */
+  countUnchangedRuns(
+    currentStatus: string,
+    versionOrBuildChanged: boolean,
+    previousData: AppStoreCacheEntry | GooglePlayCacheEntry | AmazonCacheEntry | HuaweiCacheEntry | undefined
+  ): number {
+    if (!previousData || versionOrBuildChanged || previousData.status !== currentStatus) {
+      return 0;
+    }
+    return (previousData.consecutiveUnchangedRuns ?? 0) + 1;
+  }
+
+  /**
+   * Append an entry to the status history when the status differs from the latest one,
+   * keeping at most historyLimit entries
+   */
+  appendHistory(
+    history: StatusHistoryEntry[] | undefined,
+    entry: StatusHistoryEntry
+  ): StatusHistoryEntry[] {
+    const entries = history ? [...history] : [];
+    const latest = entries[entries.length - 1];
+
+    if (!latest || latest.status !== entry.status) {
+      entries.push(entry);
+    }
+
+    return entries.slice(-this.config.historyLimit);
+  }
+
+  /**
+   * Seconds spent in the current status, measured from the history entry where it began
+   */
+  getCurrentStatusDuration(history: StatusHistoryEntry[], now: Date): number | undefined {
+    const latest = history[history.length - 1];
+    if (!latest) {
+      return undefined;
+    }
+
+    const since = Date.parse(latest.timestamp);
+    if (Number.isNaN(since)) {
+      return undefined;
+    }
+
+    return Math.max(0, Math.floor((now.getTime() - since) / 1000));
+  }
+
+  /**
+   * Check if the status changed more than window.maxTransitions times within the last
+   * window.windowHours. Each history entry after the first is one transition, so the
+   * window can't look further back than history-limit entries.
+   */
+  detectFlapping(history: StatusHistoryEntry[], window: FlappingConfig, now: Date): boolean {
+    const since = now.getTime() - window.windowHours * 60 * 60 * 1000;
+    const transitions = history.slice(1).filter((entry) => Date.parse(entry.timestamp) >= since).length;
+
+    const flapping = transitions > window.maxTransitions;
+    if (flapping) {
+      core.info(`Status changed ${transitions} times in the last ${window.windowHours} hours, flapping detected`);
+    }
+
+    return flapping;
+  }
+
+  /**
+   * Check if status moved from an approved status back into rejection or removal
+   */
+  hasRegressedFromApproval(
+    platform: CachePlatform,
+    currentStatus: string,
+    previousData: AppStoreCacheEntry | GooglePlayCacheEntry | AmazonCacheEntry | HuaweiCacheEntry | undefined
+  ): boolean {
+    if (!previousData) {
+      return false;
+    }
+
+    const wasApproved = matchesAny(previousData.status, this.approvedStatuses);
+    const isRejected = matchesAny(currentStatus, [...this.rejectedStatuses, ...REMOVED_STATUSES]);
+
+    const regressed = wasApproved && isRejected;
+    if (regressed) {
+      core.info(`${platform} regressed from approval: ${previousData.status} -> ${currentStatus}`);
+    }
+
+    return regressed;
+  }
+}
+
+// GITHUB_API_URL points at the GitHub Enterprise Server API on self-hosted instances
+function getGistUrl(gist: GistCacheConfig): string {
+  return `${process.env.GITHUB_API_URL || DEFAULT_GITHUB_API_URL}/gists/${encodeURIComponent(gist.gistId)}`;
+}
+
+function getGistHeaders(gist: GistCacheConfig): Record<string, string> {
+  return {
+    Accept: 'application/vnd.github+json',
+    Authorization: `Bearer ${gist.token}`,
+    'X-GitHub-Api-Version': '2022-11-28',
+  };
 }
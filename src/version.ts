@@ -0,0 +1,14 @@
+// ncc inlines package.json when bundling, so dist/index.js carries the version it was built from.
+// require keeps package.json out of tsc's rootDir.
+// eslint-disable-next-line @typescript-eslint/no-var-requires
+const packageJson = require('../package.json');
+
+export const VERSION: string = packageJson.version;
+
+/**
+ * Version with the ref the action was invoked at (e.g. "v0.1.0 (v0)"), for logs and message footers
+ */
+export function getVersionLabel(): string {
+  const ref = process.env.GITHUB_ACTION_REF;
+  return `store-review-monitor v${VERSION}${ref ? ` (${ref})` : ''}`;
+}